@@ -0,0 +1,166 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import "fmt"
+
+// getAs converts the value of the specified key into dest with the same conversions as Scan;
+// ConversionOverflow errors are returned as is.
+func (d *Dictionary) getAs(key, dest interface{}) error {
+	v, ok := d.Get(key)
+	if !ok {
+		return ErrKeyNotFound
+	}
+	err := convertAssign(v, dest)
+	if _, ok := err.(ConversionOverflow); err != nil && !ok {
+		return fmt.Errorf("key %s: %v", FormatText(key), err)
+	}
+	return err
+}
+
+// GetString returns the value of the specified key as a string; byte slices are converted.
+// ErrKeyNotFound is returned if the key does not exist.
+func (d *Dictionary) GetString(key interface{}) (string, error) {
+	var s string
+	err := d.getAs(key, &s)
+	return s, err
+}
+
+// GetBytes returns the value of the specified key as a byte slice
+func (d *Dictionary) GetBytes(key interface{}) ([]byte, error) {
+	var b []byte
+	err := d.getAs(key, &b)
+	return b, err
+}
+
+// GetInt returns the value of the specified key as an int
+func (d *Dictionary) GetInt(key interface{}) (int, error) {
+	var i int
+	err := d.getAs(key, &i)
+	return i, err
+}
+
+// GetInt64 returns the value of the specified key as an int64; narrower integers are widened.
+func (d *Dictionary) GetInt64(key interface{}) (int64, error) {
+	var i int64
+	err := d.getAs(key, &i)
+	return i, err
+}
+
+// GetFloat64 returns the value of the specified key as a float64; float32 values and
+// integers up to 32 bits are widened.
+func (d *Dictionary) GetFloat64(key interface{}) (float64, error) {
+	var f float64
+	err := d.getAs(key, &f)
+	return f, err
+}
+
+// GetBool returns the value of the specified key as a bool
+func (d *Dictionary) GetBool(key interface{}) (bool, error) {
+	var b bool
+	err := d.getAs(key, &b)
+	return b, err
+}
+
+// GetList returns the value of the specified key as a List
+func (d *Dictionary) GetList(key interface{}) (List, error) {
+	var l List
+	err := d.getAs(key, &l)
+	return l, err
+}
+
+// GetDict returns the value of the specified key as a Dictionary
+func (d *Dictionary) GetDict(key interface{}) (Dictionary, error) {
+	var dict Dictionary
+	err := d.getAs(key, &dict)
+	return dict, err
+}
+
+// getAs converts the value at the specified index into dest with the same conversions as Scan;
+// ConversionOverflow errors are returned as is.
+func (l *List) getAs(i int, dest interface{}) error {
+	v, err := l.Get(i)
+	if err != nil {
+		return err
+	}
+	err = convertAssign(v, dest)
+	if _, ok := err.(ConversionOverflow); err != nil && !ok {
+		return fmt.Errorf("index %d: %v", i, err)
+	}
+	return err
+}
+
+// GetString returns the value at the specified index as a string; byte slices are converted.
+// ErrIndexOutOfRange is returned if the index is out of the list bounds.
+func (l *List) GetString(i int) (string, error) {
+	var s string
+	err := l.getAs(i, &s)
+	return s, err
+}
+
+// GetBytes returns the value at the specified index as a byte slice
+func (l *List) GetBytes(i int) ([]byte, error) {
+	var b []byte
+	err := l.getAs(i, &b)
+	return b, err
+}
+
+// GetInt returns the value at the specified index as an int
+func (l *List) GetInt(i int) (int, error) {
+	var n int
+	err := l.getAs(i, &n)
+	return n, err
+}
+
+// GetInt64 returns the value at the specified index as an int64; narrower integers are widened.
+func (l *List) GetInt64(i int) (int64, error) {
+	var n int64
+	err := l.getAs(i, &n)
+	return n, err
+}
+
+// GetFloat64 returns the value at the specified index as a float64; float32 values and
+// integers up to 32 bits are widened.
+func (l *List) GetFloat64(i int) (float64, error) {
+	var f float64
+	err := l.getAs(i, &f)
+	return f, err
+}
+
+// GetBool returns the value at the specified index as a bool
+func (l *List) GetBool(i int) (bool, error) {
+	var b bool
+	err := l.getAs(i, &b)
+	return b, err
+}
+
+// GetList returns the value at the specified index as a List
+func (l *List) GetList(i int) (List, error) {
+	var v List
+	err := l.getAs(i, &v)
+	return v, err
+}
+
+// GetDict returns the value at the specified index as a Dictionary
+func (l *List) GetDict(i int) (Dictionary, error) {
+	var d Dictionary
+	err := l.getAs(i, &d)
+	return d, err
+}
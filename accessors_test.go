@@ -0,0 +1,93 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import "testing"
+
+func TestDictionaryAccessors(t *testing.T) {
+	t.Parallel()
+
+	var d Dictionary
+	d.Add([]byte("name"), []byte("ubuntu.iso"))
+	d.Add("size", int32(70000))
+	d.Add("ratio", float32(0.5))
+	d.Add("paused", false)
+	d.Add("files", NewList(int8(1), []byte("x"), int64(1)<<40, true))
+	d.Add("peers", Dictionary{})
+
+	name, err := d.GetString("name")
+	if err != nil || name != "ubuntu.iso" {
+		t.Errorf("unexpected name %q (%v)", name, err)
+	}
+	size, err := d.GetInt64([]byte("size"))
+	if err != nil || size != 70000 {
+		t.Errorf("unexpected size %d (%v)", size, err)
+	}
+	f, err := d.GetFloat64("size")
+	if err != nil || f != 70000 {
+		t.Errorf("unexpected float size %v (%v)", f, err)
+	}
+	ratio, err := d.GetFloat64("ratio")
+	if err != nil || ratio != 0.5 {
+		t.Errorf("unexpected ratio %v (%v)", ratio, err)
+	}
+	paused, err := d.GetBool("paused")
+	if err != nil || paused {
+		t.Errorf("unexpected paused %v (%v)", paused, err)
+	}
+	if _, err := d.GetDict("peers"); err != nil {
+		t.Error(err)
+	}
+	if _, err := d.GetString("missing"); err != ErrKeyNotFound {
+		t.Errorf("expected ErrKeyNotFound but got %v", err)
+	}
+	if _, err := d.GetBool("name"); err == nil {
+		t.Error("expected conversion failure")
+	}
+
+	files, err := d.GetList("files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := files.GetInt(0)
+	if err != nil || i != 1 {
+		t.Errorf("unexpected int %d (%v)", i, err)
+	}
+	s, err := files.GetString(1)
+	if err != nil || s != "x" {
+		t.Errorf("unexpected string %q (%v)", s, err)
+	}
+	if _, err := files.GetInt64(4); err != ErrIndexOutOfRange {
+		t.Errorf("expected ErrIndexOutOfRange but got %v", err)
+	}
+
+	var small Dictionary
+	small.Add("value", int64(1)<<40)
+	var l List
+	l.Add(int64(1) << 40)
+	for _, err := range []error{
+		small.getAs("value", new(int32)),
+		l.getAs(0, new(int16)),
+	} {
+		if _, ok := err.(ConversionOverflow); !ok {
+			t.Errorf("expected ConversionOverflow but got %v", err)
+		}
+	}
+}
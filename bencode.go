@@ -0,0 +1,360 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrBencodeUnsortedKeys is the error returned when bencode dictionary keys are not sorted or are duplicated
+	ErrBencodeUnsortedKeys = errors.New("bencode dictionary keys are not sorted or not unique")
+)
+
+// BencodeDecoder implements a bencode decoder producing the same values as the rencode Decoder:
+// List, Dictionary, []byte and integers.
+type BencodeDecoder struct {
+	r      io.Reader
+	peeked int
+}
+
+// NewBencodeDecoder returns a bencode decoder that sources all bytes from the specified reader
+func NewBencodeDecoder(r io.Reader) *BencodeDecoder {
+	return &BencodeDecoder{r: r, peeked: -1}
+}
+
+func (b *BencodeDecoder) readByte() (byte, error) {
+	if b.peeked >= 0 {
+		c := byte(b.peeked)
+		b.peeked = -1
+		return c, nil
+	}
+
+	var data [1]byte
+	_, err := io.ReadFull(b.r, data[:])
+	return data[0], err
+}
+
+func (b *BencodeDecoder) unreadByte(c byte) {
+	b.peeked = int(c)
+}
+
+// readInteger reads the digits of an integer until the specified delimiter
+func (b *BencodeDecoder) readInteger(delim byte) (string, error) {
+	var digits []byte
+	for {
+		c, err := b.readByte()
+		if err != nil {
+			return "", err
+		}
+		if c == delim {
+			break
+		}
+		if (c < '0' || c > '9') && !(c == '-' && len(digits) == 0) {
+			return "", fmt.Errorf("invalid character %q in bencode integer", c)
+		}
+		if len(digits) >= MAX_INT_LENGTH {
+			return "", fmt.Errorf("bencode integer is longer than %d characters", MAX_INT_LENGTH)
+		}
+		digits = append(digits, c)
+	}
+
+	s := string(digits)
+	if s == "" || s == "-" || s == "-0" || (len(s) > 1 && s[0] == '0') || (len(s) > 2 && s[:2] == "-0") {
+		return "", fmt.Errorf("invalid bencode integer %q", s)
+	}
+	return s, nil
+}
+
+// narrowInteger returns the value as the narrowest integer type, like the rencode Decoder would
+// return it after encoding; integers which do not fit in an int64 are returned as big.Int.
+func narrowInteger(s string) (interface{}, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		var bi big.Int
+		if _, ok := bi.SetString(s, 10); !ok {
+			return nil, fmt.Errorf("invalid integer %q", s)
+		}
+		return bi, nil
+	}
+
+	switch {
+	case math.MinInt8 <= i && i <= math.MaxInt8:
+		return int8(i), nil
+	case math.MinInt16 <= i && i <= math.MaxInt16:
+		return int16(i), nil
+	case math.MinInt32 <= i && i <= math.MaxInt32:
+		return int32(i), nil
+	}
+	return i, nil
+}
+
+// DecodeNext returns the next available object stored in the bencode stream.
+// If no more objects are available, an io.EOF error will be returned.
+func (b *BencodeDecoder) DecodeNext() (interface{}, error) {
+	c, err := b.readByte()
+	if err != nil {
+		return nil, err
+	}
+	return b.decode(c)
+}
+
+func (b *BencodeDecoder) decodeInner() (interface{}, bool, error) {
+	c, err := b.readByte()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if c == 'e' {
+		return nil, true, nil
+	}
+	v, err := b.decode(c)
+	return v, false, err
+}
+
+func (b *BencodeDecoder) decode(c byte) (interface{}, error) {
+	switch {
+	case c == 'i':
+		s, err := b.readInteger('e')
+		if err != nil {
+			return nil, err
+		}
+		return narrowInteger(s)
+	case c == 'l':
+		var l List
+		for {
+			v, end, err := b.decodeInner()
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return l, nil
+			}
+			l.Add(v)
+		}
+	case c == 'd':
+		var d Dictionary
+		var last []byte
+		for {
+			k, end, err := b.decodeInner()
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return d, nil
+			}
+			key, ok := k.([]byte)
+			if !ok {
+				return nil, fmt.Errorf("bencode dictionary key of type %T, expected byte string", k)
+			}
+			if d.Length() > 0 && bytes.Compare(last, key) >= 0 {
+				return nil, ErrBencodeUnsortedKeys
+			}
+			last = key
+
+			v, end, err := b.decodeInner()
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return nil, fmt.Errorf("missing value for bencode dictionary key %q", key)
+			}
+			d.Add(key, v)
+		}
+	case '0' <= c && c <= '9':
+		b.unreadByte(c)
+		s, err := b.readInteger(':')
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("invalid bencode string length %d", n)
+		}
+		// the length is not trusted: memory is allocated as data is actually read
+		data, err := readFullBounded(b.r, n)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return data, err
+	}
+
+	return nil, fmt.Errorf("invalid bencode type character %q", c)
+}
+
+// BencodeEncoder implements a bencode encoder
+type BencodeEncoder struct {
+	w io.Writer
+}
+
+// NewBencodeEncoder returns a bencode encoder that writes on specified Writer
+func NewBencodeEncoder(w io.Writer) BencodeEncoder {
+	return BencodeEncoder{w}
+}
+
+// Encode will ingest and encode multiple values of the following supported types:
+//  - big.Int
+//  - List, Dictionary (with string or []byte keys only, which are sorted when encoding)
+//  - []byte, string
+//  - int8, int16, int32, int64, int
+//  - uint8, uint16, uint32, uint64, uint
+// Bencode has no representation for booleans, floats and None.
+func (e *BencodeEncoder) Encode(values ...interface{}) error {
+	for _, v := range values {
+		err := e.encodeSingle(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *BencodeEncoder) encodeBytes(b []byte) error {
+	_, err := fmt.Fprintf(e.w, "%d:", len(b))
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *BencodeEncoder) encodeSingle(data interface{}) error {
+	switch x := data.(type) {
+	case List:
+		_, err := e.w.Write([]byte{'l'})
+		if err != nil {
+			return err
+		}
+		for _, v := range x.Values() {
+			err = e.encodeSingle(v)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = e.w.Write([]byte{'e'})
+		return err
+	case Dictionary:
+		type pair struct {
+			key   []byte
+			value interface{}
+		}
+		pairs := make([]pair, x.Length())
+		values := x.Values()
+		for i, k := range x.Keys() {
+			switch key := k.(type) {
+			case []byte:
+				pairs[i] = pair{key, values[i]}
+			case string:
+				pairs[i] = pair{[]byte(key), values[i]}
+			default:
+				return fmt.Errorf("bencode dictionary key of type %T, expected string or []byte", k)
+			}
+		}
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return bytes.Compare(pairs[i].key, pairs[j].key) < 0
+		})
+
+		_, err := e.w.Write([]byte{'d'})
+		if err != nil {
+			return err
+		}
+		for i, p := range pairs {
+			if i > 0 && bytes.Equal(pairs[i-1].key, p.key) {
+				return ErrBencodeUnsortedKeys
+			}
+			err = e.encodeBytes(p.key)
+			if err != nil {
+				return err
+			}
+			err = e.encodeSingle(p.value)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = e.w.Write([]byte{'e'})
+		return err
+	case []byte:
+		return e.encodeBytes(x)
+	case string:
+		return e.encodeBytes([]byte(x))
+	case big.Int:
+		_, err := fmt.Fprintf(e.w, "i%se", x.String())
+		return err
+	case int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint:
+		_, err := fmt.Fprintf(e.w, "i%de", x)
+		return err
+	}
+
+	return fmt.Errorf("could not bencode data of type %T", data)
+}
+
+// BencodeToRencode converts all the values of the bencode stream read from r to rencode values written to w
+func BencodeToRencode(w io.Writer, r io.Reader) error {
+	d := NewBencodeDecoder(r)
+	e := NewEncoder(w)
+	for {
+		v, err := d.DecodeNext()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = e.Encode(v)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// RencodeToBencode converts all the values of the rencode stream read from r to bencode values written to w;
+// an error is returned for values which bencode cannot represent.
+func RencodeToBencode(w io.Writer, r io.Reader) error {
+	d := NewDecoder(r)
+	e := NewBencodeEncoder(w)
+	for {
+		v, err := d.DecodeNext()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = e.Encode(v)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,150 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBencodeEncodeSortsKeys(t *testing.T) {
+	t.Parallel()
+
+	var info Dictionary
+	info.Add("name", "ubuntu.iso")
+	info.Add("length", int64(3221225472))
+	info.Add("piece length", 262144)
+
+	var d Dictionary
+	d.Add("info", info)
+	d.Add([]byte("announce"), "http://tracker.example/announce")
+	d.Add("announce-list", NewList(NewList("a"), NewList("b")))
+
+	var b bytes.Buffer
+	e := NewBencodeEncoder(&b)
+	err := e.Encode(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "d8:announce31:http://tracker.example/announce13:announce-listll1:ael1:bee" +
+		"4:infod6:lengthi3221225472e4:name10:ubuntu.iso12:piece lengthi262144eee"
+	if b.String() != expected {
+		t.Fatalf("unexpected bencoding %q", b.String())
+	}
+
+	dec := NewBencodeDecoder(&b)
+	v, err := dec.DecodeNext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := v.(Dictionary)
+	i, ok := decoded.Get("info")
+	if !ok {
+		t.Fatal("info key not found")
+	}
+	infoDict := i.(Dictionary)
+	length, _ := infoDict.Get("length")
+	if length != int64(3221225472) {
+		t.Errorf("expected int64 length but got %v (%T)", length, length)
+	}
+	pieceLength, _ := infoDict.Get("piece length")
+	if pieceLength != int32(262144) {
+		t.Errorf("expected int32 piece length but got %v (%T)", pieceLength, pieceLength)
+	}
+}
+
+func TestBencodeDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"i-0e",
+		"i03e",
+		"ie",
+		"i1x2e",
+		"-1:a",
+		"5:abc",
+		"l1:a",
+		"d1:b1:a1:a1:be",
+		"d1:a1:a1:a1:be",
+		"di1ei2ee",
+		"d1:ae",
+		"x",
+		// lengths which must not be allocated upfront
+		"999999999999999999:abc",
+		"2000000000:abc",
+		strings.Repeat("9", 64) + ":abc",
+		// integers are limited to MAX_INT_LENGTH characters like in rencode
+		"i" + strings.Repeat("1", MAX_INT_LENGTH+1) + "e",
+	} {
+		d := NewBencodeDecoder(strings.NewReader(input))
+		_, err := d.DecodeNext()
+		if err == nil {
+			t.Errorf("expected failure for %q", input)
+		}
+	}
+
+	d := NewBencodeDecoder(strings.NewReader("i-" + strings.Repeat("1", MAX_INT_LENGTH-1) + "e"))
+	if _, err := d.DecodeNext(); err != nil {
+		t.Errorf("unexpected failure for integer of %d characters: %v", MAX_INT_LENGTH, err)
+	}
+}
+
+func TestBencodeUnsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []interface{}{true, 1.5, nil} {
+		var b bytes.Buffer
+		e := NewBencodeEncoder(&b)
+		err := e.Encode(NewList(v))
+		if err == nil {
+			t.Errorf("expected failure for %v", v)
+		}
+	}
+}
+
+func TestBencodeTranscode(t *testing.T) {
+	t.Parallel()
+
+	var value big.Int
+	value.SetUint64(^uint64(0))
+	value.Mul(&value, big.NewInt(32))
+
+	input := "d3:bigi" + value.String() + "e4:listli-1ei1000ei100000ei10000000000ee3:str3:fooe"
+
+	var r bytes.Buffer
+	err := BencodeToRencode(&r, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	err = RencodeToBencode(&b, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.String() != input {
+		t.Errorf("transcoding mismatch:\n%s\n%s", input, b.String())
+	}
+}
@@ -0,0 +1,487 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"unicode/utf8"
+)
+
+/*
+CBOR mapping used by TranscodeToCBOR and TranscodeFromCBOR:
+
+	rencode              CBOR
+	-------              ----
+	None                 null
+	bool                 false, true
+	integers             unsigned or negative integer, with the shortest argument
+	big.Int              unsigned or negative integer, or bignum (tag 2 or 3) beyond 64 bits
+	float32, float64     single and double precision float
+	byte string          text string if valid UTF-8, byte string otherwise
+	list                 array; indefinite-length for terminated lists
+	dictionary           map; indefinite-length for terminated dictionaries
+
+Lossy cases when converting from CBOR: text and byte strings both become byte strings, undefined
+becomes None, half precision floats become float32 and integer types are narrowed to the smallest
+rencode type holding the value. The self-described CBOR tag (55799) is skipped; all other tags and
+simple values have no rencode representation and are reported as errors.
+*/
+
+// CBOR major types
+const (
+	cborUnsigned = iota << 5
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+const (
+	cborIndefinite       = 31
+	cborBreak            = 0xff
+	cborTagBignum        = 2
+	cborTagNegBignum     = 3
+	cborTagSelfDescribed = 55799
+)
+
+// cborWriter transcodes the rencode token stream to CBOR
+type cborWriter struct {
+	w io.Writer
+}
+
+// TranscodeToCBOR converts all the values of the rencode stream read from r to CBOR values written to w
+func TranscodeToCBOR(w io.Writer, r io.Reader) error {
+	d := NewDecoder(r)
+	c := cborWriter{w}
+	for {
+		t, err := d.NextToken()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = c.token(d, t)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c cborWriter) write(b ...byte) error {
+	_, err := c.w.Write(b)
+	return err
+}
+
+// head writes the initial byte of a data item of the specified major type followed by the shortest argument
+func (c cborWriter) head(major byte, v uint64) error {
+	m := msgpackWriter{c.w}
+	switch {
+	case v < 24:
+		return c.write(major | byte(v))
+	case v <= math.MaxUint8:
+		return m.writeUint(major|24, 1, v)
+	case v <= math.MaxUint16:
+		return m.writeUint(major|25, 2, v)
+	case v <= math.MaxUint32:
+		return m.writeUint(major|26, 4, v)
+	}
+	return m.writeUint(major|27, 8, v)
+}
+
+func (c cborWriter) integer(v int64) error {
+	if v < 0 {
+		return c.head(cborNegative, uint64(-1-v))
+	}
+	return c.head(cborUnsigned, uint64(v))
+}
+
+func (c cborWriter) bigInteger(v big.Int) error {
+	major, tag := byte(cborUnsigned), uint64(cborTagBignum)
+	var n big.Int
+	n.Set(&v)
+	if n.Sign() < 0 {
+		// negative integers are encoded as -1-n
+		major, tag = cborNegative, cborTagNegBignum
+		n.Neg(&n)
+		n.Sub(&n, big.NewInt(1))
+	}
+
+	if n.IsUint64() {
+		return c.head(major, n.Uint64())
+	}
+	err := c.head(cborTag, tag)
+	if err != nil {
+		return err
+	}
+	return c.bytes(cborBytes, n.Bytes())
+}
+
+func (c cborWriter) bytes(major byte, b []byte) error {
+	err := c.head(major, uint64(len(b)))
+	if err != nil {
+		return err
+	}
+	_, err = c.w.Write(b)
+	return err
+}
+
+// container transcodes the elements of a list (pairs = 1) or dictionary (pairs = 2)
+func (c cborWriter) container(d *Decoder, t Token, pairs int) error {
+	major := byte(cborArray)
+	if pairs == 2 {
+		major = cborMap
+	}
+
+	if t.Length >= 0 {
+		err := c.head(major, uint64(t.Length))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < t.Length*pairs; i++ {
+			e, err := nextInnerToken(d)
+			if err != nil {
+				return err
+			}
+			err = c.token(d, e)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := c.write(major | cborIndefinite)
+	if err != nil {
+		return err
+	}
+	for n := 0; ; n++ {
+		e, err := nextInnerToken(d)
+		if err != nil {
+			return err
+		}
+		if e.Kind == EndToken {
+			if n%pairs != 0 {
+				return fmt.Errorf("dictionary key without value at offset %d", e.Offset)
+			}
+			return c.write(cborBreak)
+		}
+		err = c.token(d, e)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c cborWriter) token(d *Decoder, t Token) error {
+	switch t.Kind {
+	case EndToken:
+		return fmt.Errorf("unexpected terminator at offset %d", t.Offset)
+	case ListToken:
+		return c.container(d, t, 1)
+	case DictToken:
+		return c.container(d, t, 2)
+	}
+
+	switch v := t.Value.(type) {
+	case nil:
+		return c.write(cborSimple | 22)
+	case bool:
+		if v {
+			return c.write(cborSimple | 21)
+		}
+		return c.write(cborSimple | 20)
+	case int8:
+		return c.integer(int64(v))
+	case int16:
+		return c.integer(int64(v))
+	case int32:
+		return c.integer(int64(v))
+	case int64:
+		return c.integer(v)
+	case big.Int:
+		return c.bigInteger(v)
+	case float32:
+		return msgpackWriter{c.w}.writeUint(cborSimple|26, 4, uint64(math.Float32bits(v)))
+	case float64:
+		return msgpackWriter{c.w}.writeUint(cborSimple|27, 8, math.Float64bits(v))
+	case []byte:
+		if utf8.Valid(v) {
+			return c.bytes(cborText, v)
+		}
+		return c.bytes(cborBytes, v)
+	}
+
+	return fmt.Errorf("unexpected value of type %T at offset %d", t.Value, t.Offset)
+}
+
+// cborReader reads CBOR data items
+type cborReader struct {
+	transcodeReader
+}
+
+// head reads the initial byte and the argument of a data item; indefinite is set for
+// indefinite-length strings and containers.
+func (c cborReader) head(initial byte) (major byte, arg uint64, indefinite bool, err error) {
+	major, info := initial&0xe0, initial&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), false, nil
+	case info <= 27:
+		arg, err = c.readUint(1 << (info - 24))
+		return major, arg, false, err
+	case info == cborIndefinite && major != cborUnsigned && major != cborNegative && major != cborTag:
+		return major, 0, true, nil
+	}
+	return major, 0, false, fmt.Errorf("invalid CBOR initial byte 0x%x", initial)
+}
+
+func (c cborReader) readInitial() (byte, error) {
+	b, err := c.readFull(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (c cborReader) length(arg uint64) (int, error) {
+	if arg > math.MaxInt32 {
+		return 0, fmt.Errorf("CBOR length %d is too large", arg)
+	}
+	return int(arg), nil
+}
+
+// readString reads the content of a byte or text string, concatenating the chunks of indefinite-length strings
+func (c cborReader) readString(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		n, err := c.length(arg)
+		if err != nil {
+			return nil, err
+		}
+		return c.readFull(n)
+	}
+
+	var b bytes.Buffer
+	for {
+		initial, err := c.readInitial()
+		if err != nil {
+			return nil, err
+		}
+		if initial == cborBreak {
+			return b.Bytes(), nil
+		}
+		chunkMajor, chunkArg, chunkIndefinite, err := c.head(initial)
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkIndefinite {
+			return nil, fmt.Errorf("invalid chunk 0x%x in indefinite-length CBOR string", initial)
+		}
+		chunk, err := c.readString(major, chunkArg, false)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(chunk)
+	}
+}
+
+// TranscodeFromCBOR converts all the CBOR values read from r to rencode values written to w
+func TranscodeFromCBOR(w io.Writer, r io.Reader) error {
+	c := cborReader{transcodeReader{r}}
+	e := NewEncoder(w)
+	for {
+		var b [1]byte
+		_, err := io.ReadFull(r, b[:])
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = e.fromCBOR(c, b[0])
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Encoder) fromCBOR(c cborReader, initial byte) error {
+	if initial == cborBreak {
+		return fmt.Errorf("unexpected CBOR break")
+	}
+	major, arg, indefinite, err := c.head(initial)
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case cborUnsigned:
+		return r.encodeUnsigned(arg)
+	case cborNegative:
+		if arg <= math.MaxInt64 {
+			return r.encodeSingle(-1 - int64(arg))
+		}
+		var i big.Int
+		i.SetUint64(arg)
+		i.Neg(&i)
+		i.Sub(&i, big.NewInt(1))
+		return r.encodeSingle(i)
+	case cborBytes, cborText:
+		b, err := c.readString(major, arg, indefinite)
+		if err != nil {
+			return err
+		}
+		return r.EncodeBytes(b)
+	case cborArray, cborMap:
+		return r.cborContainer(c, major, arg, indefinite)
+	case cborTag:
+		return r.cborTag(c, arg)
+	}
+
+	switch initial & 0x1f {
+	case 20:
+		return r.EncodeBool(false)
+	case 21:
+		return r.EncodeBool(true)
+	case 22, 23:
+		return r.EncodeNone()
+	case 25:
+		return r.EncodeFloat32(halfToFloat32(uint16(arg)))
+	case 26:
+		return r.EncodeFloat32(math.Float32frombits(uint32(arg)))
+	case 27:
+		return r.EncodeFloat64(math.Float64frombits(arg))
+	}
+	return fmt.Errorf("CBOR simple value %d has no rencode representation", arg)
+}
+
+func (r *Encoder) cborContainer(c cborReader, major byte, arg uint64, indefinite bool) error {
+	n := -1
+	if !indefinite {
+		var err error
+		n, err = c.length(arg)
+		if err != nil {
+			return err
+		}
+	}
+
+	var terminated bool
+	var err error
+	pairs := 1
+	if major == cborMap {
+		pairs = 2
+		terminated, err = r.BeginDict(n)
+	} else {
+		terminated, err = r.BeginList(n)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := 0; indefinite || i < n*pairs; i++ {
+		initial, err := c.readInitial()
+		if err != nil {
+			return err
+		}
+		if indefinite && initial == cborBreak {
+			if i%pairs != 0 {
+				return fmt.Errorf("CBOR map key without value")
+			}
+			break
+		}
+		err = r.fromCBOR(c, initial)
+		if err != nil {
+			return err
+		}
+	}
+
+	if terminated {
+		return r.EndContainer()
+	}
+	return nil
+}
+
+func (r *Encoder) cborTag(c cborReader, tag uint64) error {
+	initial, err := c.readInitial()
+	if err != nil {
+		return err
+	}
+
+	switch tag {
+	case cborTagSelfDescribed:
+		return r.fromCBOR(c, initial)
+	case cborTagBignum, cborTagNegBignum:
+		major, arg, indefinite, err := c.head(initial)
+		if err != nil {
+			return err
+		}
+		if major != cborBytes {
+			return fmt.Errorf("CBOR bignum content of major type %d, expected byte string", major>>5)
+		}
+		b, err := c.readString(major, arg, indefinite)
+		if err != nil {
+			return err
+		}
+
+		var i big.Int
+		i.SetBytes(b)
+		if tag == cborTagNegBignum {
+			i.Neg(&i)
+			i.Sub(&i, big.NewInt(1))
+		}
+		if i.IsInt64() {
+			return r.encodeSingle(i.Int64())
+		}
+		// like any other big integer, bignums longer than MAX_INT_LENGTH digits are rejected
+		return r.encodeSingle(i)
+	}
+
+	return fmt.Errorf("CBOR tag %d has no rencode representation", tag)
+}
+
+// halfToFloat32 converts an IEEE 754 half precision float to float32
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+
+	switch {
+	case exp == 0x1f:
+		// infinity and NaN
+		return math.Float32frombits(sign | 0xff<<23 | frac<<13)
+	case exp == 0:
+		// zero and subnormal numbers
+		f := float32(frac) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+}
@@ -0,0 +1,129 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestCBOREncoding(t *testing.T) {
+	t.Parallel()
+
+	var r bytes.Buffer
+	e := NewEncoder(&r)
+	err := e.Encode(NewList(int8(1), int8(-100), "a", nil, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c bytes.Buffer
+	err = TranscodeToCBOR(&c, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0x85, 0x01, 0x38, 0x63, 0x61, 'a', 0xf6, 0xf5}
+	if !bytes.Equal(c.Bytes(), expected) {
+		t.Errorf("expected % x but got % x", expected, c.Bytes())
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var value big.Int
+	value.SetUint64(^uint64(0))
+	value.Mul(&value, big.NewInt(-3))
+
+	input := transcodeSample(t)
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input = append(input, b.Bytes()...)
+
+	var c bytes.Buffer
+	err = TranscodeToCBOR(&c, bytes.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r bytes.Buffer
+	err = TranscodeFromCBOR(&r, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(input, r.Bytes()) {
+		t.Errorf("transcoding mismatch:\n% x\n% x", input, r.Bytes())
+	}
+}
+
+func TestCBORDecoding(t *testing.T) {
+	t.Parallel()
+
+	// self-described tag, indefinite-length map with a chunked text string key and a half float value
+	input := []byte{0xd9, 0xd9, 0xf7, 0xbf, 0x7f, 0x61, 'a', 0x61, 'b', 0xff, 0xf9, 0x3e, 0x00, 0xff}
+
+	var r bytes.Buffer
+	err := TranscodeFromCBOR(&r, bytes.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDecoder(&r)
+	v, err := d.DecodeNext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict := v.(Dictionary)
+	value, ok := dict.Get("ab")
+	if !ok {
+		t.Fatal("ab key not found")
+	}
+	if value != float32(1.5) {
+		t.Errorf("expected float32 1.5 but got %v (%T)", value, value)
+	}
+}
+
+func TestCBORUnsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range [][]byte{
+		{0xc1, 0x00},
+		{0xe0},
+		{0xff},
+		{0x1c},
+		{0x62, 'a'},
+		{0x9f, 0x01},
+		{0xc2, 0x01},
+		// bignums with more than MAX_INT_LENGTH digits
+		append([]byte{0xc2, 0x58, 40}, bytes.Repeat([]byte{0xff}, 40)...),
+		append([]byte{0xc3, 0x58, 40}, bytes.Repeat([]byte{0xff}, 40)...),
+	} {
+		var r bytes.Buffer
+		err := TranscodeFromCBOR(&r, bytes.NewReader(input))
+		if err == nil {
+			t.Errorf("expected failure for % x", input)
+		}
+	}
+}
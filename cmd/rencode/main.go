@@ -0,0 +1,436 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+/*
+Command rencode inspects rencode streams, for example captured Deluge RPC traffic.
+
+Usage:
+
+	rencode <command> [-z] [file ...]
+
+The commands are:
+
+	dump      print values as an indented tree with types and offsets
+	validate  check that input is well-formed and report the first error location
+	stats     print counts of each type, maximum nesting depth and largest strings
+	tojson    convert to the tagged JSON representation, one document per line
+	fromjson  convert from the tagged JSON representation
+
+Input is read from the specified files or from standard input if none is specified;
+with -z the input is zlib-decompressed first.
+*/
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdm85/go-rencode"
+)
+
+type command struct {
+	name, help string
+	run        func(fs *flag.FlagSet, stdout io.Writer) func(name string, r io.Reader) error
+}
+
+var commands = []command{
+	{"dump", "print values as an indented tree with types and offsets", dumpCommand},
+	{"validate", "check that input is well-formed and report the first error location", validateCommand},
+	{"stats", "print counts of each type, maximum nesting depth and largest strings", statsCommand},
+	{"tojson", "convert to the tagged JSON representation, one document per line", toJSONCommand},
+	{"fromjson", "convert from the tagged JSON representation", fromJSONCommand},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: rencode <command> [-z] [file ...]")
+	fmt.Fprintln(w, "\nThe commands are:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "\t%-10s%s\n", c.name, c.help)
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+			break
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "rencode: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	decompress := fs.Bool("z", false, "zlib-decompress input")
+	process := cmd.run(fs, stdout)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+
+	status := 0
+	for _, name := range inputs {
+		err := processInput(name, stdin, *decompress, process)
+		if err != nil {
+			fmt.Fprintf(stderr, "rencode: %v\n", err)
+			status = 1
+		}
+	}
+	return status
+}
+
+func processInput(name string, stdin io.Reader, decompress bool, process func(string, io.Reader) error) error {
+	var r io.Reader = stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	r = bufio.NewReader(r)
+
+	if decompress {
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	return process(name, r)
+}
+
+// roles of a token within its container
+const (
+	roleElement = ""
+	roleKey     = "key "
+	roleValue   = "value "
+)
+
+// visitor is invoked for every token of a value, with the nesting depth and the role within its container
+type visitor func(t rencode.Token, depth int, role string)
+
+// walk reads all the tokens of the value started by t and passes them to visit
+func walk(d *rencode.Decoder, t rencode.Token, depth int, role string, visit visitor) error {
+	visit(t, depth, role)
+
+	switch t.Kind {
+	case rencode.EndToken:
+		return fmt.Errorf("offset %d: unexpected terminator", t.Offset)
+	case rencode.ListToken, rencode.DictToken:
+		for i := 0; t.Length < 0 || i < t.Length; i++ {
+			c, err := nextToken(d)
+			if err != nil {
+				return err
+			}
+			if c.Kind == rencode.EndToken && t.Length < 0 {
+				return nil
+			}
+
+			if t.Kind == rencode.ListToken {
+				err = walk(d, c, depth+1, roleElement, visit)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			err = walk(d, c, depth+1, roleKey, visit)
+			if err != nil {
+				return err
+			}
+			c, err = nextToken(d)
+			if err != nil {
+				return err
+			}
+			if c.Kind == rencode.EndToken && t.Length < 0 {
+				// like DecodeNext, a key without value right before the terminator has value None
+				return nil
+			}
+			err = walk(d, c, depth+1, roleValue, visit)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nextToken returns the next token within a value, reporting where the stream was truncated or malformed
+func nextToken(d *rencode.Decoder) (rencode.Token, error) {
+	t, err := d.NextToken()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return t, fmt.Errorf("offset %d: %v", d.Offset(), err)
+	}
+	return t, nil
+}
+
+// walkAll visits all the values of the stream and returns the count of top-level values
+func walkAll(r io.Reader, visit visitor) (int, error) {
+	d := rencode.NewDecoder(r)
+	for n := 0; ; n++ {
+		t, err := d.NextToken()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("offset %d: %v", d.Offset(), err)
+		}
+
+		err = walk(d, t, 0, roleElement, visit)
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// typeName returns a short description of the type of a token
+func typeName(t rencode.Token) string {
+	switch t.Kind {
+	case rencode.ListToken:
+		return "list"
+	case rencode.DictToken:
+		return "dict"
+	case rencode.EndToken:
+		return "end"
+	}
+
+	switch t.Value.(type) {
+	case nil:
+		return "none"
+	case []byte:
+		return "bytes"
+	case big.Int:
+		return "bigint"
+	}
+	return fmt.Sprintf("%T", t.Value)
+}
+
+func dumpCommand(fs *flag.FlagSet, stdout io.Writer) func(string, io.Reader) error {
+	return func(name string, r io.Reader) error {
+		w := bufio.NewWriter(stdout)
+		defer w.Flush()
+
+		_, err := walkAll(r, func(t rencode.Token, depth int, role string) {
+			indent := strings.Repeat("  ", depth)
+
+			switch t.Kind {
+			case rencode.ListToken, rencode.DictToken:
+				length := "terminated"
+				if t.Length >= 0 {
+					length = fmt.Sprint(t.Length)
+				}
+				fmt.Fprintf(w, "%08x  %s%s%s (%s)\n", t.Offset, indent, role, typeName(t), length)
+			default:
+				value := fmt.Sprint(t.Value)
+				switch v := t.Value.(type) {
+				case []byte:
+					value = fmt.Sprintf("%q", v)
+				case big.Int:
+					value = v.String()
+				case nil:
+					value = "None"
+				}
+				fmt.Fprintf(w, "%08x  %s%s%s %s\n", t.Offset, indent, role, typeName(t), value)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	}
+}
+
+func validateCommand(fs *flag.FlagSet, stdout io.Writer) func(string, io.Reader) error {
+	quiet := fs.Bool("q", false, "do not print anything for valid input")
+	return func(name string, r io.Reader) error {
+		n, err := walkAll(r, func(rencode.Token, int, string) {})
+		if err != nil {
+			return fmt.Errorf("%s: value %d: %v", name, n, err)
+		}
+		if !*quiet {
+			fmt.Fprintf(stdout, "%s: ok, %d values\n", name, n)
+		}
+		return nil
+	}
+}
+
+// countFlag is an integer flag which cannot be negative
+type countFlag int
+
+func (c *countFlag) String() string {
+	return strconv.Itoa(int(*c))
+}
+
+func (c *countFlag) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errors.New("negative count")
+	}
+	*c = countFlag(n)
+	return nil
+}
+
+type stringStat struct {
+	offset int64
+	length int
+}
+
+func statsCommand(fs *flag.FlagSet, stdout io.Writer) func(string, io.Reader) error {
+	top := countFlag(5)
+	fs.Var(&top, "n", "count of largest strings to report")
+	return func(name string, r io.Reader) error {
+		counts := map[string]int{}
+		maxDepth := 0
+		var stringStats []stringStat
+
+		n, err := walkAll(r, func(t rencode.Token, depth int, role string) {
+			counts[typeName(t)]++
+			if depth > maxDepth {
+				maxDepth = depth
+			}
+			if b, ok := t.Value.([]byte); ok {
+				stringStats = append(stringStats, stringStat{t.Offset, len(b)})
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("%s: value %d: %v", name, n, err)
+		}
+
+		w := bufio.NewWriter(stdout)
+		defer w.Flush()
+
+		fmt.Fprintf(w, "%s:\n", name)
+		fmt.Fprintf(w, "  values     %d\n", n)
+		fmt.Fprintf(w, "  max depth  %d\n", maxDepth)
+
+		var types []string
+		for t := range counts {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		fmt.Fprintln(w, "  types:")
+		for _, t := range types {
+			fmt.Fprintf(w, "    %-10s %d\n", t, counts[t])
+		}
+
+		sort.SliceStable(stringStats, func(i, j int) bool {
+			return stringStats[i].length > stringStats[j].length
+		})
+		if len(stringStats) > int(top) {
+			stringStats = stringStats[:top]
+		}
+		if len(stringStats) != 0 {
+			fmt.Fprintln(w, "  largest strings:")
+			for _, s := range stringStats {
+				fmt.Fprintf(w, "    %08x   %d bytes\n", s.offset, s.length)
+			}
+		}
+		return nil
+	}
+}
+
+func toJSONCommand(fs *flag.FlagSet, stdout io.Writer) func(string, io.Reader) error {
+	indent := fs.Bool("indent", false, "indent JSON documents")
+	return func(name string, r io.Reader) error {
+		if !*indent {
+			err := rencode.ToJSON(stdout, r)
+			if err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			return nil
+		}
+
+		var b bytes.Buffer
+		err := rencode.ToJSON(&b, r)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+
+		d := json.NewDecoder(&b)
+		for d.More() {
+			var doc json.RawMessage
+			err = d.Decode(&doc)
+			if err != nil {
+				return err
+			}
+
+			var out bytes.Buffer
+			err = json.Indent(&out, doc, "", "  ")
+			if err != nil {
+				return err
+			}
+			out.WriteByte('\n')
+			_, err = out.WriteTo(stdout)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func fromJSONCommand(fs *flag.FlagSet, stdout io.Writer) func(string, io.Reader) error {
+	return func(name string, r io.Reader) error {
+		w := bufio.NewWriter(stdout)
+		err := rencode.FromJSON(r, w)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return w.Flush()
+	}
+}
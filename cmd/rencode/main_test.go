@@ -0,0 +1,171 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func encode(t *testing.T, values ...interface{}) []byte {
+	var b bytes.Buffer
+	e := rencode.NewEncoder(&b)
+	err := e.Encode(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	var d rencode.Dictionary
+	d.Add("alpha", rencode.NewList(int8(1), nil))
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"dump"}, bytes.NewReader(encode(t, d)), &stdout, &stderr)
+	if status != 0 {
+		t.Fatalf("unexpected exit status %d: %s", status, stderr.String())
+	}
+
+	expected := `00000000  dict (1)
+00000001    key bytes "alpha"
+00000007    value list (2)
+00000008      int8 1
+00000009      none None
+`
+	if stdout.String() != expected {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	data := encode(t, "hello", rencode.NewList(int16(1234), "world"))
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"validate"}, bytes.NewReader(data), &stdout, &stderr)
+	if status != 0 || !strings.Contains(stdout.String(), "ok, 2 values") {
+		t.Fatalf("unexpected result %d: %s%s", status, stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	status = run([]string{"validate"}, bytes.NewReader(data[:len(data)-2]), &stdout, &stderr)
+	if status != 1 {
+		t.Fatalf("expected failure but got exit status %d", status)
+	}
+	if !strings.Contains(stderr.String(), "value 1: offset 14: unexpected EOF") {
+		t.Errorf("unexpected error: %s", stderr.String())
+	}
+}
+
+func TestWalkMatchesDecoder(t *testing.T) {
+	t.Parallel()
+
+	key := []byte{rencode.STR_FIXED_START + 1, 'a'}
+	for _, input := range [][]byte{
+		append(append([]byte{rencode.CHR_DICT}, key...), rencode.CHR_TERM),
+		append(append(append([]byte{rencode.CHR_DICT}, key...), 1), rencode.CHR_TERM),
+		append(append(append([]byte{rencode.CHR_DICT}, key...), 1), append(key, rencode.CHR_TERM)...),
+		append([]byte{rencode.DICT_FIXED_START + 1}, append(key, rencode.CHR_TERM)...),
+		append([]byte{rencode.CHR_LIST}, key...),
+		{rencode.CHR_LIST, rencode.CHR_TERM, rencode.CHR_TERM},
+		{rencode.CHR_TERM},
+		{rencode.LIST_FIXED_START + 1, rencode.CHR_TERM},
+	} {
+		_, walkErr := walkAll(bytes.NewReader(input), func(rencode.Token, int, string) {})
+
+		var decodeErr error
+		d := rencode.NewDecoder(bytes.NewReader(input))
+		for decodeErr == nil {
+			_, decodeErr = d.DecodeNext()
+		}
+		if decodeErr == io.EOF {
+			decodeErr = nil
+		}
+
+		if (walkErr == nil) != (decodeErr == nil) {
+			t.Errorf("%v: walk error %v, decoder error %v", input, walkErr, decodeErr)
+		}
+	}
+}
+
+func TestStatsCompressed(t *testing.T) {
+	t.Parallel()
+
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(encode(t, rencode.NewList("a", rencode.NewList("bcd", 1.5))))
+	zw.Close()
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"stats", "-z", "-n", "1"}, &z, &stdout, &stderr)
+	if status != 0 {
+		t.Fatalf("unexpected exit status %d: %s", status, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, expected := range []string{"max depth  2", "bytes      2", "list       2", "3 bytes"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, " 1 bytes") {
+		t.Errorf("expected only the largest string to be reported:\n%s", out)
+	}
+
+	stderr.Reset()
+	status = run([]string{"stats", "-n", "-1"}, bytes.NewReader(nil), &stdout, &stderr)
+	if status != 2 || !strings.Contains(stderr.String(), "negative count") {
+		t.Errorf("expected usage error for negative count, got status %d: %s", status, stderr.String())
+	}
+}
+
+func TestJSONCommands(t *testing.T) {
+	t.Parallel()
+
+	data := encode(t, rencode.NewList("a", float32(2.5)), int64(-5))
+
+	var j, stderr bytes.Buffer
+	status := run([]string{"tojson", "-indent"}, bytes.NewReader(data), &j, &stderr)
+	if status != 0 {
+		t.Fatalf("unexpected exit status %d: %s", status, stderr.String())
+	}
+	if !strings.Contains(j.String(), "\n  \"list\": [") {
+		t.Errorf("expected indented output:\n%s", j.String())
+	}
+
+	var result bytes.Buffer
+	status = run([]string{"fromjson"}, &j, &result, &stderr)
+	if status != 0 {
+		t.Fatalf("unexpected exit status %d: %s", status, stderr.String())
+	}
+	if !bytes.Equal(data, result.Bytes()) {
+		t.Errorf("round-trip mismatch: %v != %v", data, result.Bytes())
+	}
+}
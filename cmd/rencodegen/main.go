@@ -0,0 +1,638 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+/*
+Command rencodegen generates MarshalRencode and UnmarshalRencode methods for struct types, which encode
+and decode them as dictionaries directly with the Encoder and Decoder, without using reflection.
+
+Usage:
+
+	rencodegen -type T1,T2 [-exclude annotation] [-o file] [directory]
+
+The types must be declared in the package in the specified directory (default is the current one);
+the generated code is written to the specified file or to standard output.
+
+Dictionaries are mapped to structs as with ToStruct and FromStruct, including the optional and
+default=<value> tag options (defaults must be scalar); time values use the format set on the Encoder
+and Decoder instead of the time tag option. Fields with the excluded annotation are not
+encoded and their keys are ignored when decoding. Fields can be of the types supported by Encode,
+of named types with those underlying types, of types also generated by rencodegen, interface{} and
+pointers, slices, arrays and maps of them.
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("rencodegen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typeNames := fs.String("type", "", "comma-separated list of struct type names (required)")
+	exclude := fs.String("exclude", "", "annotation of the fields to exclude")
+	output := fs.String("o", "", "output file (default is standard output)")
+	err := fs.Parse(args)
+	if err != nil {
+		return 2
+	}
+	if *typeNames == "" || fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "usage: rencodegen -type T1,T2 [-exclude annotation] [-o file] [directory]")
+		return 2
+	}
+
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	src, err := generate(dir, strings.Split(*typeNames, ","), *exclude)
+	if err != nil {
+		fmt.Fprintln(stderr, "rencodegen:", err)
+		return 1
+	}
+
+	if *output == "" {
+		stdout.Write(src)
+		return 0
+	}
+	err = ioutil.WriteFile(*output, src, 0644)
+	if err != nil {
+		fmt.Fprintln(stderr, "rencodegen:", err)
+		return 1
+	}
+	return 0
+}
+
+// generator holds the state of the code generation for a package
+type generator struct {
+	b bytes.Buffer
+	// types are all the type declarations of the package
+	types map[string]ast.Expr
+	// generated are the types for which methods are generated
+	generated map[string]bool
+	exclude   string
+	imports   map[string]bool
+	// tmp is the counter of temporary variables
+	tmp int
+}
+
+// generate parses the package in dir and returns the source code of the methods for the specified types
+func generate(dir string, typeNames []string, exclude string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	g := generator{
+		types:     map[string]ast.Expr{},
+		generated: map[string]bool{},
+		exclude:   exclude,
+		imports:   map[string]bool{"github.com/gdm85/go-rencode": true},
+	}
+	var pkgName string
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					g.types[ts.Name.Name] = ts.Type
+				}
+			}
+		}
+	}
+
+	for _, name := range typeNames {
+		if _, ok := g.types[name].(*ast.StructType); !ok {
+			return nil, fmt.Errorf("type %s: not a struct type declared in %s", name, dir)
+		}
+		g.generated[name] = true
+	}
+	for _, name := range typeNames {
+		err = g.generateType(name, g.types[name].(*ast.StructType))
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %v", name, err)
+		}
+	}
+
+	// standard library packages come first
+	var std, imports []string
+	for path := range g.imports {
+		if strings.Contains(path, ".") {
+			imports = append(imports, strconv.Quote(path))
+		} else {
+			std = append(std, strconv.Quote(path))
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(imports)
+	if len(std) != 0 {
+		imports = append(append(std, ""), imports...)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by rencodegen; DO NOT EDIT.\n\npackage %s\n\nimport (\n%s\n)\n", pkgName, strings.Join(imports, "\n"))
+	b.Write(g.b.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %v", err)
+	}
+	return src, nil
+}
+
+// field is a struct field to be encoded and decoded
+type field struct {
+	goName, name string
+	typ          ast.Expr
+	optional     bool
+	// defaultValue is the Go expression of the default value, if any
+	defaultValue string
+	excluded     bool
+}
+
+func (g *generator) structFields(st *ast.StructType) ([]field, error) {
+	var fields []field
+	for _, f := range st.Fields.List {
+		var tag string
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(s).Get("rencode")
+		}
+
+		names := f.Names
+		if len(names) == 0 {
+			// embedded fields are flattened by ToStruct, unless tagged as noinline
+			if types.ExprString(f.Type) == "rencode.Positional" {
+				return nil, fmt.Errorf("positional structs are not supported")
+			}
+			name := embeddedName(f.Type)
+			if name == nil || !hasOption(tag, "noinline") {
+				return nil, fmt.Errorf("embedded field %s is not supported", types.ExprString(f.Type))
+			}
+			names = []*ast.Ident{name}
+		}
+
+		for _, n := range names {
+			if !n.IsExported() {
+				continue
+			}
+			fd := field{goName: n.Name, name: rencode.ToSnakeCase(n.Name), typ: f.Type}
+			err := g.parseTag(&fd, tag)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", n.Name, err)
+			}
+			fields = append(fields, fd)
+		}
+	}
+	return fields, nil
+}
+
+// embeddedName returns the implicit name of an embedded field, or nil
+func embeddedName(t ast.Expr) *ast.Ident {
+	if st, ok := t.(*ast.StarExpr); ok {
+		t = st.X
+	}
+	switch x := t.(type) {
+	case *ast.Ident:
+		return x
+	case *ast.SelectorExpr:
+		return x.Sel
+	}
+	return nil
+}
+
+// hasOption returns true if the rencode tag has the specified option
+func hasOption(tag, option string) bool {
+	if i := strings.Index(tag, "default="); i >= 0 {
+		tag = tag[:i]
+	}
+	for _, o := range strings.Split(tag, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTag parses the options of a rencode tag, as ToStruct does
+func (g *generator) parseTag(fd *field, tag string) error {
+	for tag != "" {
+		var option string
+		if strings.HasPrefix(tag, "default=") {
+			v, err := rencode.ParseText(strings.TrimPrefix(tag, "default="))
+			if err != nil {
+				return fmt.Errorf("default value: %v", err)
+			}
+			fd.defaultValue, err = goLiteral(v, fd.typ)
+			return err
+		}
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			option, tag = tag[:i], tag[i+1:]
+		} else {
+			option, tag = tag, ""
+		}
+
+		if strings.HasPrefix(option, "time=") {
+			return fmt.Errorf("time option is not supported, use SetTimeFormat of the Encoder and Decoder")
+		}
+		switch option {
+		case "remain", "inline":
+			return fmt.Errorf("%s option is not supported", option)
+		case "noinline":
+		case "optional":
+			fd.optional = true
+		case "":
+		default:
+			if g.exclude != "" && option == g.exclude {
+				fd.excluded = true
+			}
+		}
+	}
+	return nil
+}
+
+// goLiteral returns the Go expression of a scalar default value
+func goLiteral(v interface{}, typ ast.Expr) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "nil", nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case int8, int16, int32, int64:
+		return fmt.Sprint(x), nil
+	case big.Int:
+		return x.String(), nil
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), nil
+	case []byte:
+		if isBytes(typ) {
+			return "[]byte(" + strconv.Quote(string(x)) + ")", nil
+		}
+		return strconv.Quote(string(x)), nil
+	}
+	return "", fmt.Errorf("default value of type %T is not supported", v)
+}
+
+func isBytes(t ast.Expr) bool {
+	at, ok := t.(*ast.ArrayType)
+	if !ok || at.Len != nil {
+		return false
+	}
+	id, ok := at.Elt.(*ast.Ident)
+	return ok && (id.Name == "byte" || id.Name == "uint8")
+}
+
+func (g *generator) generateType(name string, st *ast.StructType) error {
+	fields, err := g.structFields(st)
+	if err != nil {
+		return err
+	}
+
+	var encoded []field
+	for _, f := range fields {
+		if !f.excluded {
+			encoded = append(encoded, f)
+		}
+	}
+
+	g.tmp = 0
+	fmt.Fprintf(&g.b, "\n// MarshalRencode encodes %s as a dictionary, like FromStruct\n", name)
+	fmt.Fprintf(&g.b, "func (x %s) MarshalRencode(e *rencode.Encoder) error {\n", name)
+	fmt.Fprintf(&g.b, "term, err := e.BeginDict(%d)\nif err != nil {\nreturn err\n}\n", len(encoded))
+	for _, f := range encoded {
+		fmt.Fprintf(&g.b, "if err := e.Encode(%q); err != nil {\nreturn err\n}\n", f.name)
+		err = g.encode("x."+f.goName, f.typ)
+		if err != nil {
+			return fmt.Errorf("field %s: %v", f.goName, err)
+		}
+	}
+	fmt.Fprintf(&g.b, "if term {\nreturn e.EndContainer()\n}\nreturn nil\n}\n")
+
+	g.tmp = 0
+	fmt.Fprintf(&g.b, "\n// UnmarshalRencode decodes %s from a dictionary, like ToStruct\n", name)
+	fmt.Fprintf(&g.b, "func (x *%s) UnmarshalRencode(d *rencode.Decoder) error {\n", name)
+	if len(encoded) > 0 {
+		fmt.Fprintf(&g.b, "var seen [%d]bool\n", len(encoded))
+	}
+	fmt.Fprintf(&g.b, "err := d.DecodeDict(func() error {\nvar key []byte\nif err := d.Scan(&key); err != nil {\nreturn err\n}\nswitch string(key) {\n")
+	for i, f := range encoded {
+		fmt.Fprintf(&g.b, "case %q:\n", f.name)
+		fmt.Fprintf(&g.b, "if seen[%d] {\nreturn &rencode.FieldError{Path: %q, Err: rencode.ErrKeyAlreadyExists}\n}\nseen[%d] = true\n", i, f.name, i)
+		err = g.decode("x."+f.goName, f.typ)
+		if err != nil {
+			return fmt.Errorf("field %s: %v", f.goName, err)
+		}
+	}
+	var excluded []string
+	for _, f := range fields {
+		if f.excluded {
+			excluded = append(excluded, strconv.Quote(f.name))
+		}
+	}
+	if len(excluded) > 0 {
+		fmt.Fprintf(&g.b, "case %s:\n_, err := d.DecodeNext()\nreturn err\n", strings.Join(excluded, ", "))
+	}
+	fmt.Fprintf(&g.b, "default:\nreturn &rencode.FieldError{Path: string(key), Err: rencode.ErrUnknownKey}\n}\nreturn nil\n})\n")
+	fmt.Fprintf(&g.b, "if err != nil {\nreturn err\n}\n")
+	for i, f := range encoded {
+		switch {
+		case f.defaultValue != "":
+			fmt.Fprintf(&g.b, "if !seen[%d] {\nx.%s = %s\n}\n", i, f.goName, f.defaultValue)
+		case !f.optional:
+			fmt.Fprintf(&g.b, "if !seen[%d] {\nreturn &rencode.FieldError{Path: %q, Err: rencode.ErrMissingField}\n}\n", i, f.name)
+		}
+	}
+	fmt.Fprintf(&g.b, "return nil\n}\n")
+	return nil
+}
+
+// typeKind classifies the types of fields
+type typeKind int
+
+const (
+	invalidKind typeKind = iota
+	// scalarKind are the types handled by Encode and Scan
+	scalarKind
+	// namedScalarKind are the named types with a scalar underlying type
+	namedScalarKind
+	interfaceKind
+	// generatedKind are the types for which methods are generated
+	generatedKind
+	pointerKind
+	sliceKind
+	arrayKind
+	mapKind
+)
+
+var scalarTypes = map[string]bool{
+	"bool": true, "string": true, "float32": true, "float64": true, "byte": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"rencode.List": true, "rencode.Dictionary": true, "rencode.Tuple": true, "rencode.Set": true, "big.Int": true,
+	"time.Time": true, "time.Duration": true,
+}
+
+// packages are the import paths of the packages of the scalar types
+var packages = map[string]string{
+	"rencode": "github.com/gdm85/go-rencode",
+	"big":     "math/big",
+	"time":    "time",
+}
+
+// classify returns the kind of a type and, for named types, the underlying type
+func (g *generator) classify(t ast.Expr) (typeKind, ast.Expr) {
+	switch x := t.(type) {
+	case *ast.Ident:
+		if scalarTypes[x.Name] {
+			return scalarKind, nil
+		}
+		if g.generated[x.Name] {
+			return generatedKind, nil
+		}
+		underlying, ok := g.types[x.Name]
+		if !ok {
+			return invalidKind, nil
+		}
+		kind, _ := g.classify(underlying)
+		switch kind {
+		case scalarKind:
+			return namedScalarKind, underlying
+		case sliceKind, arrayKind, mapKind:
+			return kind, underlying
+		}
+	case *ast.SelectorExpr:
+		if scalarTypes[types.ExprString(x)] {
+			return scalarKind, nil
+		}
+	case *ast.InterfaceType:
+		if len(x.Methods.List) == 0 {
+			return interfaceKind, nil
+		}
+	case *ast.StarExpr:
+		return pointerKind, nil
+	case *ast.ArrayType:
+		if isBytes(x) {
+			return scalarKind, nil
+		}
+		if x.Len == nil {
+			return sliceKind, nil
+		}
+		return arrayKind, nil
+	case *ast.MapType:
+		return mapKind, nil
+	}
+	return invalidKind, nil
+}
+
+func (g *generator) newVar(prefix string) string {
+	g.tmp++
+	return fmt.Sprintf("%s%d", prefix, g.tmp)
+}
+
+// encode writes the code encoding the value of expression src of type t
+func (g *generator) encode(src string, t ast.Expr) error {
+	kind, underlying := g.classify(t)
+	switch kind {
+	case scalarKind, interfaceKind:
+		fmt.Fprintf(&g.b, "if err := e.Encode(%s); err != nil {\nreturn err\n}\n", src)
+	case namedScalarKind:
+		fmt.Fprintf(&g.b, "if err := e.Encode(%s(%s)); err != nil {\nreturn err\n}\n", g.typeString(underlying), src)
+	case generatedKind:
+		fmt.Fprintf(&g.b, "if err := %s.MarshalRencode(e); err != nil {\nreturn err\n}\n", src)
+	case pointerKind:
+		fmt.Fprintf(&g.b, "if %s == nil {\nif err := e.EncodeNone(); err != nil {\nreturn err\n}\n} else {\n", src)
+		err := g.encode("(*"+src+")", t.(*ast.StarExpr).X)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "}\n")
+	case sliceKind, arrayKind:
+		term, v := g.newVar("term"), g.newVar("v")
+		fmt.Fprintf(&g.b, "%s, err := e.BeginList(len(%s))\nif err != nil {\nreturn err\n}\n", term, src)
+		fmt.Fprintf(&g.b, "for _, %s := range %s {\n", v, src)
+		err := g.encode(v, elemType(t, underlying))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "}\nif %s {\nif err := e.EndContainer(); err != nil {\nreturn err\n}\n}\n", term)
+	case mapKind:
+		mt := mapType(t, underlying)
+		keyKind, _ := g.classify(mt.Key)
+		if keyKind != scalarKind && keyKind != namedScalarKind {
+			return fmt.Errorf("map key type %s is not supported", types.ExprString(mt.Key))
+		}
+		g.imports["sort"] = true
+		term, keys, k := g.newVar("term"), g.newVar("keys"), g.newVar("k")
+		fmt.Fprintf(&g.b, "%s, err := e.BeginDict(len(%s))\nif err != nil {\nreturn err\n}\n", term, src)
+		fmt.Fprintf(&g.b, "%s := make([]%s, 0, len(%s))\nfor %s := range %s {\n%s = append(%s, %s)\n}\n",
+			keys, g.typeString(mt.Key), src, k, src, keys, keys, k)
+		fmt.Fprintf(&g.b, "sort.Slice(%s, func(i, j int) bool {\nreturn %s[i] < %s[j]\n})\n", keys, keys, keys)
+		fmt.Fprintf(&g.b, "for _, %s := range %s {\n", k, keys)
+		err := g.encode(k, mt.Key)
+		if err != nil {
+			return err
+		}
+		err = g.encode(src+"["+k+"]", mt.Value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "}\nif %s {\nif err := e.EndContainer(); err != nil {\nreturn err\n}\n}\n", term)
+	default:
+		return g.unsupported(t)
+	}
+	return nil
+}
+
+// decode writes the code decoding the next value into the addressable expression target of type t
+func (g *generator) decode(target string, t ast.Expr) error {
+	kind, underlying := g.classify(t)
+	switch kind {
+	case scalarKind:
+		fmt.Fprintf(&g.b, "if err := d.Scan(&%s); err != nil {\nreturn err\n}\n", target)
+	case interfaceKind:
+		v := g.newVar("v")
+		fmt.Fprintf(&g.b, "%s, err := d.DecodeNext()\nif err != nil {\nreturn err\n}\n%s = %s\n", v, target, v)
+	case namedScalarKind:
+		v := g.newVar("v")
+		fmt.Fprintf(&g.b, "var %s %s\nif err := d.Scan(&%s); err != nil {\nreturn err\n}\n%s = %s(%s)\n",
+			v, g.typeString(underlying), v, target, g.typeString(t), v)
+	case generatedKind:
+		fmt.Fprintf(&g.b, "if err := %s.UnmarshalRencode(d); err != nil {\nreturn err\n}\n", target)
+	case pointerKind:
+		elem := t.(*ast.StarExpr).X
+		fmt.Fprintf(&g.b, "if none, err := d.DecodeNone(); err != nil {\nreturn err\n} else if none {\n%s = nil\n} else {\n%s = new(%s)\n",
+			target, target, g.typeString(elem))
+		err := g.decode("(*"+target+")", elem)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "}\n")
+	case sliceKind:
+		v := g.newVar("v")
+		elem := elemType(t, underlying)
+		fmt.Fprintf(&g.b, "%s = make(%s, 0)\nif err := d.DecodeList(func() error {\nvar %s %s\n", target, g.typeString(t), v, g.typeString(elem))
+		err := g.decode(v, elem)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "%s = append(%s, %s)\nreturn nil\n}); err != nil {\nreturn err\n}\n", target, target, v)
+	case arrayKind:
+		g.imports["fmt"] = true
+		i := g.newVar("i")
+		fmt.Fprintf(&g.b, "%s := 0\nif err := d.DecodeList(func() error {\nif %s == len(%s) {\nreturn fmt.Errorf(\"expected list of %%d elements\", len(%s))\n}\n",
+			i, i, target, target)
+		err := g.decode(target+"["+i+"]", elemType(t, underlying))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "%s++\nreturn nil\n}); err != nil {\nreturn err\n}\n", i)
+		fmt.Fprintf(&g.b, "if %s != len(%s) {\nreturn fmt.Errorf(\"expected list of %%d elements, got %%d\", len(%s), %s)\n}\n", i, target, target, i)
+	case mapKind:
+		mt := mapType(t, underlying)
+		k, v := g.newVar("k"), g.newVar("v")
+		fmt.Fprintf(&g.b, "%s = make(%s)\nif err := d.DecodeDict(func() error {\nvar %s %s\n", target, g.typeString(t), k, g.typeString(mt.Key))
+		err := g.decode(k, mt.Key)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "var %s %s\n", v, g.typeString(mt.Value))
+		err = g.decode(v, mt.Value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "%s[%s] = %s\nreturn nil\n}); err != nil {\nreturn err\n}\n", target, k, v)
+	default:
+		return g.unsupported(t)
+	}
+	return nil
+}
+
+// typeString returns the expression of a type for the generated code, importing the referenced packages
+func (g *generator) typeString(t ast.Expr) string {
+	ast.Inspect(t, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && packages[id.Name] != "" {
+				g.imports[packages[id.Name]] = true
+			}
+		}
+		return true
+	})
+	return types.ExprString(t)
+}
+
+// unsupported returns the error for a type which cannot be encoded or decoded
+func (g *generator) unsupported(t ast.Expr) error {
+	if id, ok := t.(*ast.Ident); ok {
+		if _, ok := g.types[id.Name].(*ast.StructType); ok {
+			return fmt.Errorf("type %s is not supported, it must be specified with -type as well", id.Name)
+		}
+	}
+	return fmt.Errorf("type %s is not supported", types.ExprString(t))
+}
+
+// elemType returns the element type of a slice or array type, possibly named
+func elemType(t, underlying ast.Expr) ast.Expr {
+	if underlying != nil {
+		t = underlying
+	}
+	return t.(*ast.ArrayType).Elt
+}
+
+// mapType returns the map type of a type, possibly named
+func mapType(t, underlying ast.Expr) *ast.MapType {
+	if underlying != nil {
+		t = underlying
+	}
+	return t.(*ast.MapType)
+}
@@ -0,0 +1,119 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedUpToDate(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-type", "Status,Tracker,Peer", "-exclude", "private", "../../internal/gentest"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit status %d: %s", code, stderr.String())
+	}
+
+	expected, err := ioutil.ReadFile("../../internal/gentest/types_rencode.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stdout.Bytes(), expected) {
+		t.Error("internal/gentest/types_rencode.go is not up to date, run go generate ./internal/gentest")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "rencodegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package p
+
+type Base struct {
+	ID int
+}
+
+type Embedded struct {
+	Base
+}
+
+type Channel struct {
+	C chan int
+}
+
+type Remain struct {
+	Rest map[string]interface{} ` + "`rencode:\",remain\"`" + `
+}
+
+type ListDefault struct {
+	L []int ` + "`rencode:\"default=[1]\"`" + `
+}
+
+type Nested struct {
+	B Base
+	Base ` + "`rencode:\"noinline\"`" + `
+}
+
+type Pair struct {
+	rencode.Positional
+	A int
+}
+
+type NotStruct int
+`
+	err = ioutil.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for typeName, expected := range map[string]string{
+		"Embedded":    "embedded field",
+		"Channel":     "type chan int is not supported",
+		"Remain":      "remain option is not supported",
+		"ListDefault": "default value of type rencode.List is not supported",
+		"Nested":      "type Base is not supported, it must be specified with -type",
+		"Pair":        "positional structs are not supported",
+		"NotStruct":   "not a struct type",
+		"Missing":     "not a struct type",
+	} {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-type", typeName, dir}, &stdout, &stderr)
+		if code != 1 || !strings.Contains(stderr.String(), expected) {
+			t.Errorf("%s: expected failure with %q but got status %d: %s", typeName, expected, code, stderr.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-type", "Base,Nested", dir}, &stdout, &stderr)
+	if code != 0 || !strings.Contains(stdout.String(), "func (x *Nested) UnmarshalRencode(d *rencode.Decoder) error") {
+		t.Errorf("unexpected status %d: %s", code, stderr.String())
+	}
+}
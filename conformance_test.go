@@ -0,0 +1,168 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// conformanceVector is a golden vector generated with the reference Python implementation,
+// see testdata/conformance/generate.py; a nil encoded value means that the value cannot be encoded
+// and a nil value with decodeError set means that the encoded value cannot be decoded.
+type conformanceVector struct {
+	line        int
+	encoded     []byte
+	floatBits   string
+	value       interface{}
+	decodeError bool
+}
+
+func readConformanceVectors(t *testing.T) []conformanceVector {
+	f, err := os.Open("testdata/conformance/vectors.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var vectors []conformanceVector
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if strings.HasPrefix(s.Text(), "#") {
+			continue
+		}
+		fields := strings.SplitN(s.Text(), "\t", 3)
+		if len(fields) != 3 {
+			t.Fatalf("line %d: expected 3 fields but got %d", line, len(fields))
+		}
+
+		vector := conformanceVector{line: line, floatBits: fields[1]}
+		if fields[0] != "error" {
+			var err error
+			vector.encoded, err = hex.DecodeString(fields[0])
+			if err != nil {
+				t.Fatalf("line %d: %v", line, err)
+			}
+		}
+		if fields[2] == "error" {
+			vector.decodeError = true
+		} else {
+			value, err := ParseText(fields[2])
+			if err != nil {
+				t.Fatalf("line %d: %v", line, err)
+			}
+			if fields[1] == "32" {
+				value = toFloat32(value)
+			}
+			vector.value = value
+		}
+		vectors = append(vectors, vector)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+// toFloat32 converts all float64 values to float32, as encoded by Python rencode with float_bits=32
+func toFloat32(v interface{}) interface{} {
+	switch x := v.(type) {
+	case float64:
+		return float32(x)
+	case List:
+		var l List
+		for _, e := range x.Values() {
+			l.Add(toFloat32(e))
+		}
+		return l
+	case Dictionary:
+		var d Dictionary
+		values := x.Values()
+		for i, k := range x.Keys() {
+			d.Add(toFloat32(k), toFloat32(values[i]))
+		}
+		return d
+	}
+	return v
+}
+
+func TestConformanceDecode(t *testing.T) {
+	t.Parallel()
+
+	for _, vector := range readConformanceVectors(t) {
+		if vector.encoded == nil {
+			continue
+		}
+		d := NewDecoder(bytes.NewReader(vector.encoded))
+		v, err := d.DecodeNext()
+		if vector.decodeError {
+			if err == nil {
+				t.Errorf("line %d: expected failure but decoded %s", vector.line, FormatText(v))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("line %d: %v", vector.line, err)
+			continue
+		}
+		if _, err := d.DecodeNext(); err != io.EOF {
+			t.Errorf("line %d: expected end of stream but got %v", vector.line, err)
+		}
+
+		// the types must match too, since integers are decoded with the narrowest type
+		if !Equal(v, vector.value, EqualOptions{}) {
+			t.Errorf("line %d: expected %s but decoded %s (%v)", vector.line, FormatText(vector.value), FormatText(v),
+				Diff(vector.value, v))
+		}
+	}
+}
+
+func TestConformanceEncode(t *testing.T) {
+	t.Parallel()
+
+	for _, vector := range readConformanceVectors(t) {
+		if vector.decodeError {
+			continue
+		}
+		var b bytes.Buffer
+		e := NewEncoder(&b)
+		err := e.Encode(vector.value)
+		if vector.encoded == nil {
+			if err == nil {
+				t.Errorf("line %d: expected failure but encoded %x", vector.line, b.Bytes())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("line %d: %v", vector.line, err)
+			continue
+		}
+
+		if !bytes.Equal(b.Bytes(), vector.encoded) {
+			t.Errorf("line %d: expected %x but encoded %x", vector.line, vector.encoded, b.Bytes())
+		}
+	}
+}
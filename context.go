@@ -0,0 +1,168 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"context"
+	"time"
+)
+
+// readDeadliner is implemented by net.Conn and os.File
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// writeDeadliner is implemented by net.Conn and os.File
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// aLongTimeAgo is a deadline in the past, used to immediately interrupt blocked I/O
+var aLongTimeAgo = time.Unix(1, 0)
+
+// contextError returns the error of the context after an I/O failure; a read or write deadline
+// can expire slightly before the context timer fires, so an expired deadline is reported as well.
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+			err = context.DeadlineExceeded
+		}
+	}
+	return err
+}
+
+// watchContext applies the context deadline via setDeadline and interrupts any pending I/O
+// when the context is cancelled; the returned function must be called once I/O is complete
+// and restores the deadline to none.
+func watchContext(ctx context.Context, setDeadline func(time.Time) error) (func(), error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		err := setDeadline(deadline)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			setDeadline(aLongTimeAgo)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		setDeadline(time.Time{})
+	}, nil
+}
+
+// DecodeNextContext is like DecodeNext but stops waiting for data when the context is done.
+// If the underlying reader supports read deadlines (like net.Conn) the context deadline and
+// cancellation interrupt a blocked read, otherwise the context is only checked before decoding;
+// any read deadline previously set on the reader is cleared on return.
+// When decoding is interrupted the context error is returned and the stream must be considered
+// unusable, since a value might have been partially consumed.
+func (r *Decoder) DecodeNextContext(ctx context.Context) (interface{}, error) {
+	var v interface{}
+	err := r.readContext(ctx, func() error {
+		var err error
+		v, err = r.DecodeNext()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ScanContext is like Scan but stops waiting for data when the context is done; see DecodeNextContext.
+// The context is also checked between targets.
+func (r *Decoder) ScanContext(ctx context.Context, targets ...interface{}) error {
+	return r.readContext(ctx, func() error {
+		return r.scan(ctx, targets)
+	})
+}
+
+// readContext calls read, interrupting blocked reads when the context is done
+func (r *Decoder) readContext(ctx context.Context, read func() error) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	if conn, ok := r.r.(readDeadliner); ok {
+		stop, err := watchContext(ctx, conn.SetReadDeadline)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	err = read()
+	if err != nil {
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			return ctxErr
+		}
+	}
+	return err
+}
+
+// EncodeContext is like Encode but aborts when the context is done.
+// If the underlying writer supports write deadlines (like net.Conn) the context deadline and
+// cancellation interrupt a blocked write, otherwise the context is checked between values;
+// any write deadline previously set on the writer is cleared on return.
+// When encoding is interrupted the context error is returned and the stream must be considered
+// unusable, since a value might have been partially written.
+func (r *Encoder) EncodeContext(ctx context.Context, values ...interface{}) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	if conn, ok := r.w.(writeDeadliner); ok {
+		stop, err := watchContext(ctx, conn.SetWriteDeadline)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	for _, v := range values {
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = r.encodeSingle(v)
+		if err != nil {
+			if ctxErr := contextError(ctx); ctxErr != nil {
+				return ctxErr
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDecodeNextContextCancel(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	d := NewDecoder(server)
+	_, err := d.DecodeNextContext(ctx)
+	if err != context.Canceled {
+		t.Fatalf("expected %v but got %v", context.Canceled, err)
+	}
+}
+
+func TestDecodeNextContextDeadline(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	d := NewDecoder(server)
+	_, err := d.DecodeNextContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected %v but got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestEncodeDecodeContext(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	ctx := context.Background()
+	go func() {
+		e := NewEncoder(client)
+		e.EncodeContext(ctx, int16(27123), "hello world")
+	}()
+
+	var (
+		i int16
+		s string
+	)
+	d := NewDecoder(server)
+	err := d.ScanContext(ctx, &i, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 27123 || s != "hello world" {
+		t.Errorf("unexpected values %v, %q", i, s)
+	}
+
+	// a connection can still be used once the context has been released
+	go func() {
+		e := NewEncoder(client)
+		e.Encode(true)
+	}()
+	var b bool
+	err = d.Scan(&b)
+	if err != nil || !b {
+		t.Errorf("expected to read true after context use, got %v (err %v)", b, err)
+	}
+}
+
+func TestEncodeContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.EncodeContext(ctx, 1, 2, 3)
+	if err != context.Canceled {
+		t.Fatalf("expected %v but got %v", context.Canceled, err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %d bytes", b.Len())
+	}
+}
+
+type contextUnmarshaler struct {
+	called bool
+}
+
+func (u *contextUnmarshaler) UnmarshalRencode(d *Decoder) error {
+	u.called = true
+	_, err := d.DecodeNext()
+	return err
+}
+
+func TestScanContextUnmarshaler(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(int8(1), "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		u contextUnmarshaler
+		s string
+	)
+	d := NewDecoder(&b)
+	err = d.ScanContext(context.Background(), &u, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.called || s != "x" {
+		t.Errorf("unexpected scan result %v, %q", u.called, s)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.ScanContext(ctx, &s); err != context.Canceled {
+		t.Errorf("expected %v but got %v", context.Canceled, err)
+	}
+}
@@ -24,13 +24,19 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"strconv"
 )
 
 // Decoder implements a rencode decoder
 type Decoder struct {
-	r io.Reader
+	r      io.Reader
+	offset int64
+	// unread is the type code pushed back by unreadByte, if hasUnread is set
+	unread     byte
+	hasUnread  bool
+	timeFormat TimeFormat
 }
 
 var (
@@ -43,7 +49,12 @@ func init() {
 
 // NewDecoder returns a rencode decoder that sources all bytes from the specified reader
 func NewDecoder(r io.Reader) *Decoder {
-	return &Decoder{r}
+	return &Decoder{r: r}
+}
+
+// Offset returns the count of bytes consumed so far from the underlying reader
+func (r *Decoder) Offset() int64 {
+	return r.offset
 }
 
 // Dump will dump the content of the specified bytes slice to the specified writer, for debugging purposes.
@@ -91,22 +102,53 @@ func dumpValue(w io.Writer, prefix string, v interface{}) {
 }
 
 func (r *Decoder) readByte() (byte, error) {
+	if r.hasUnread {
+		r.hasUnread = false
+		r.offset++
+		return r.unread, nil
+	}
 	var data [1]byte
 	n, err := r.r.Read(data[:])
 	if n == 1 {
+		r.offset++
 		return data[0], nil
 	}
 	return 0, err
 }
 
+// unreadByte pushes back a type code which has just been read, so that the next readByte returns it again
+func (r *Decoder) unreadByte(b byte) {
+	r.unread = b
+	r.hasUnread = true
+	r.offset--
+}
+
 // readBytes fully reads bytes into a slice, or returns an error.
 func (r *Decoder) readBytes(data []byte) error {
-	_, err := io.ReadFull(r.r, data)
+	n, err := io.ReadFull(r.r, data)
+	r.offset += int64(n)
 	return err
 }
 
-// readBytesUntil will read a slice of data until 'delim' is found
-func (r *Decoder) readBytesUntil(delim byte) (data []byte, err error) {
+// readFullBounded reads exactly n bytes without allocating more memory than the data actually read
+func readFullBounded(r io.Reader, n int) ([]byte, error) {
+	const chunk = 64 * 1024
+	if n <= chunk {
+		b := make([]byte, n)
+		_, err := io.ReadFull(r, b)
+		return b, err
+	}
+
+	var b bytes.Buffer
+	m, err := io.CopyN(&b, r, int64(n))
+	if err == io.EOF && m > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return b.Bytes(), err
+}
+
+// readBytesUntil will read a slice of data until 'delim' is found, up to max bytes
+func (r *Decoder) readBytesUntil(delim byte, max int) (data []byte, err error) {
 	var b byte
 	for {
 		b, err = r.readByte()
@@ -117,6 +159,10 @@ func (r *Decoder) readBytesUntil(delim byte) (data []byte, err error) {
 		if b == delim {
 			break
 		}
+		if len(data) == max {
+			err = fmt.Errorf("delimiter %q not found within %d bytes", delim, max)
+			return
+		}
 
 		data = append(data, b)
 	}
@@ -131,7 +177,12 @@ func (r *Decoder) DecodeNext() (interface{}, error) {
 		return nil, err
 	}
 
-	return r.decode(typeCode)
+	v, err := r.decode(typeCode)
+	if err == io.EOF {
+		// the stream ended in the middle of a value
+		err = io.ErrUnexpectedEOF
+	}
+	return v, err
 }
 
 func (r *Decoder) decode(typeCode byte) (v interface{}, err error) {
@@ -150,27 +201,27 @@ func (r *Decoder) decode(typeCode byte) (v interface{}, err error) {
 		}
 		v = int8(b)
 	case CHR_INT2:
-		var data int16
-		err = binary.Read(r.r, binary.BigEndian, &data)
-		v = data
+		var data [2]byte
+		err = r.readBytes(data[:])
+		v = int16(binary.BigEndian.Uint16(data[:]))
 	case CHR_INT4:
-		var data int32
-		err = binary.Read(r.r, binary.BigEndian, &data)
-		v = data
+		var data [4]byte
+		err = r.readBytes(data[:])
+		v = int32(binary.BigEndian.Uint32(data[:]))
 	case CHR_INT8:
-		var data int64
-		err = binary.Read(r.r, binary.BigEndian, &data)
-		v = data
+		var data [8]byte
+		err = r.readBytes(data[:])
+		v = int64(binary.BigEndian.Uint64(data[:]))
 	case CHR_INT:
 		var collected []byte
-		collected, err = r.readBytesUntil(CHR_TERM)
+		collected, err = r.readBytesUntil(CHR_TERM, MAX_INT_LENGTH)
 		if err != nil {
 			return
 		}
 
 		var i big.Int
-		_, err = fmt.Sscan(string(collected), &i)
-		if err != nil {
+		if _, ok := i.SetString(string(collected), 10); !ok {
+			err = fmt.Errorf("invalid integer %q", collected)
 			return
 		}
 
@@ -181,13 +232,13 @@ func (r *Decoder) decode(typeCode byte) (v interface{}, err error) {
 			v = i
 		}
 	case CHR_FLOAT32:
-		var data float32
-		err = binary.Read(r.r, binary.BigEndian, &data)
-		v = data
+		var data [4]byte
+		err = r.readBytes(data[:])
+		v = math.Float32frombits(binary.BigEndian.Uint32(data[:]))
 	case CHR_FLOAT64:
-		var data float64
-		err = binary.Read(r.r, binary.BigEndian, &data)
-		v = data
+		var data [8]byte
+		err = r.readBytes(data[:])
+		v = math.Float64frombits(binary.BigEndian.Uint64(data[:]))
 	case CHR_LIST:
 		v, err = r.decodeList()
 		return
@@ -216,7 +267,7 @@ func (r *Decoder) decode(typeCode byte) (v interface{}, err error) {
 		}
 		if '1' <= typeCode && typeCode <= '9' {
 			var collected []byte
-			collected, err = r.readBytesUntil(':')
+			collected, err = r.readBytesUntil(':', MAX_INT_LENGTH)
 			if err != nil {
 				return
 			}
@@ -230,13 +281,23 @@ func (r *Decoder) decode(typeCode byte) (v interface{}, err error) {
 			if err != nil {
 				return
 			}
+			if stringSz < 0 {
+				err = fmt.Errorf("invalid string length %d", stringSz)
+				return
+			}
 
-			data := make([]byte, stringSz)
-			err = r.readBytes(data)
+			// do not trust the length prefix for allocation
+			var data []byte
+			data, err = readFullBounded(r.r, stringSz)
+			r.offset += int64(len(data))
 			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
 				return
 			}
 			v = data
+			return
 		}
 
 		if LIST_FIXED_START <= typeCode && typeCode <= (LIST_FIXED_START+LIST_FIXED_COUNT-1) {
@@ -256,6 +317,7 @@ func (r *Decoder) decode(typeCode byte) (v interface{}, err error) {
 				l.Add(value)
 			}
 			v = l
+			return
 		}
 		if DICT_FIXED_START <= typeCode && typeCode < DICT_FIXED_START+DICT_FIXED_COUNT {
 			var d Dictionary
@@ -280,7 +342,10 @@ func (r *Decoder) decode(typeCode byte) (v interface{}, err error) {
 				d.Add(key, value)
 			}
 			v = d
+			return
 		}
+
+		err = fmt.Errorf("invalid type code %d", typeCode)
 	} // end of switch
 
 	// AOK
@@ -0,0 +1,138 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+//go:generate go run --tags=generate generate.go -o methods_generated.go methods.spec
+
+/*
+Package deluge provides typed bindings for the Deluge RPC methods, built on top of the rencode package.
+
+The bindings are generated from the declarative method specification in methods.spec and are
+transport-agnostic: any connection to a Deluge daemon can be used as long as it implements Caller.
+
+Example:
+
+	c := deluge.NewClient(conn)
+	statuses, err := c.Core().GetTorrentsStatus(ctx, rencode.Dictionary{}, deluge.TorrentStatusKeys)
+*/
+package deluge
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// Caller is implemented by any transport capable of performing a Deluge RPC call;
+// the returned value is the rencode-decoded result of the call.
+type Caller interface {
+	Call(ctx context.Context, method string, args rencode.List, kwargs rencode.Dictionary) (interface{}, error)
+}
+
+// Client exposes typed bindings for the Deluge RPC methods, grouped by namespace
+type Client struct {
+	caller Caller
+}
+
+// NewClient returns a client that performs all calls through the specified Caller
+func NewClient(c Caller) *Client {
+	return &Client{c}
+}
+
+// toValue converts the value with rencode.ToValue, so that maps are always encoded with the same key order;
+// a value which cannot be converted is returned as-is and rejected when encoding the call.
+func toValue(v interface{}) interface{} {
+	if value, err := rencode.ToValue(v, rencode.StructOptions{}); err == nil {
+		return value
+	}
+	return v
+}
+
+// fromValue stores a decoded rencode value into the value pointed to by dest; structs are
+// mapped from dictionaries via ToStruct, while slices and maps are populated element by element.
+func fromValue(src interface{}, dest interface{}) error {
+	if p, ok := dest.(*interface{}); ok {
+		*p = src
+		return nil
+	}
+
+	dv := reflect.ValueOf(dest).Elem()
+	switch dv.Kind() {
+	case reflect.Struct:
+		if _, ok := dest.(*rencode.List); ok {
+			break
+		}
+		if _, ok := dest.(*rencode.Dictionary); ok {
+			break
+		}
+		d, ok := src.(rencode.Dictionary)
+		if !ok {
+			return fmt.Errorf("expected dictionary for %v, got %T", dv.Type(), src)
+		}
+		return d.ToStruct(dest, "")
+	case reflect.Map:
+		d, ok := src.(rencode.Dictionary)
+		if !ok {
+			return fmt.Errorf("expected dictionary for %v, got %T", dv.Type(), src)
+		}
+		m := reflect.MakeMapWithSize(dv.Type(), d.Length())
+		values := d.Values()
+		for i, k := range d.Keys() {
+			key := reflect.New(dv.Type().Key())
+			err := fromValue(k, key.Interface())
+			if err != nil {
+				return fmt.Errorf("key %v: %v", k, err)
+			}
+			value := reflect.New(dv.Type().Elem())
+			err = fromValue(values[i], value.Interface())
+			if err != nil {
+				return fmt.Errorf("key %v: %v", k, err)
+			}
+			m.SetMapIndex(key.Elem(), value.Elem())
+		}
+		dv.Set(m)
+		return nil
+	case reflect.Slice:
+		if _, ok := dest.(*[]byte); ok {
+			break
+		}
+		if src == nil {
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		l, ok := src.(rencode.List)
+		if !ok {
+			return fmt.Errorf("expected list for %v, got %T", dv.Type(), src)
+		}
+		s := reflect.MakeSlice(dv.Type(), l.Length(), l.Length())
+		for i, v := range l.Values() {
+			err := fromValue(v, s.Index(i).Addr().Interface())
+			if err != nil {
+				return fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		dv.Set(s)
+		return nil
+	}
+
+	// any other type is converted by scanning it
+	l := rencode.NewList(src)
+	return l.Scan(dest)
+}
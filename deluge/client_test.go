@@ -0,0 +1,137 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package deluge
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+type fakeCaller struct {
+	method string
+	args   rencode.List
+	result interface{}
+}
+
+func (f *fakeCaller) Call(ctx context.Context, method string, args rencode.List, kwargs rencode.Dictionary) (interface{}, error) {
+	f.method = method
+	f.args = args
+	return f.result, nil
+}
+
+func TestGetTorrentsStatus(t *testing.T) {
+	t.Parallel()
+
+	var status rencode.Dictionary
+	for _, k := range TorrentStatusKeys {
+		switch k {
+		case "name":
+			status.Add(k, []byte("ubuntu.iso"))
+		case "hash", "state", "save_path":
+			status.Add(k, []byte{})
+		default:
+			status.Add(k, int8(0))
+		}
+	}
+
+	var result rencode.Dictionary
+	result.Add([]byte("abcdef"), status)
+
+	f := &fakeCaller{result: result}
+	c := NewClient(f)
+
+	statuses, err := c.Core().GetTorrentsStatus(context.Background(), rencode.Dictionary{}, []string{"name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.method != "core.get_torrents_status" {
+		t.Errorf("unexpected method %q called", f.method)
+	}
+	if f.args.Length() != 2 {
+		t.Fatalf("expected 2 arguments but %d found", f.args.Length())
+	}
+	keys, ok := f.args.Values()[1].(rencode.List)
+	if !ok || keys.Length() != 1 {
+		t.Errorf("expected keys to be passed as a list, got %v", f.args.Values()[1])
+	}
+
+	st, ok := statuses["abcdef"]
+	if !ok {
+		t.Fatal("torrent not found in result")
+	}
+	if st.Name != "ubuntu.iso" {
+		t.Errorf("expected name %q but %q found", "ubuntu.iso", st.Name)
+	}
+}
+
+func TestMethodWithoutResult(t *testing.T) {
+	t.Parallel()
+
+	f := &fakeCaller{}
+	c := NewClient(f)
+
+	err := c.Core().PauseTorrent(context.Background(), []string{"abcdef", "012345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	err = fromValue(f.args.Values()[0], &ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[1] != "012345" {
+		t.Errorf("unexpected arguments %v", ids)
+	}
+}
+
+func TestToValueSortedKeys(t *testing.T) {
+	t.Parallel()
+
+	m := map[string]int{}
+	for _, k := range []string{"upload", "download", "max_connections", "seeding", "paused", "auto_managed"} {
+		m[k] = len(k)
+	}
+
+	var first []byte
+	for i := 0; i < 10; i++ {
+		var b bytes.Buffer
+		e := rencode.NewEncoder(&b)
+		err := e.Encode(toValue(m))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = b.Bytes()
+		} else if !bytes.Equal(first, b.Bytes()) {
+			t.Fatalf("encoding changed between calls:\n%v\n%v", first, b.Bytes())
+		}
+	}
+
+	d := toValue(m).(rencode.Dictionary)
+	keys := d.Keys()
+	if keys[0] != "auto_managed" || keys[len(keys)-1] != "upload" {
+		t.Errorf("unexpected key order %v", keys)
+	}
+}
@@ -0,0 +1,268 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package deluge
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// Backpressure specifies what happens when an event is dispatched to a subscription whose channel is full
+type Backpressure int
+
+const (
+	// Block waits until the subscriber has received the event; this stalls dispatching for all subscribers
+	Block Backpressure = iota
+	// DropNewest discards the event being dispatched
+	DropNewest
+	// DropOldest discards the oldest event queued on the channel to make room for the one being dispatched;
+	// if there is no queued event, as with unbuffered channels, the event being dispatched is discarded
+	DropOldest
+)
+
+// Subscription delivers events to a single subscriber, either on a channel or through a callback
+type Subscription struct {
+	// dropped is accessed atomically and must be 64-bit aligned
+	dropped uint64
+
+	// C is the channel on which events are delivered; it is nil for callback subscriptions
+	C <-chan Event
+
+	c      chan Event
+	fn     func(Event)
+	names  map[string]bool
+	policy Backpressure
+	d      *Dispatcher
+
+	// mu guards closing the channel against concurrent deliveries
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Dropped returns the count of events that have been discarded because the subscriber was not keeping up
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close stops delivering events to the subscription and closes its channel
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.d.remove(s)
+		// unblock any delivery in progress before closing the channel
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		if s.c != nil {
+			close(s.c)
+		}
+		s.mu.Unlock()
+	})
+}
+
+func (s *Subscription) wants(e Event) bool {
+	return len(s.names) == 0 || s.names[e.EventName()]
+}
+
+func (s *Subscription) deliver(e Event) {
+	if s.fn != nil {
+		s.fn(e)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	switch s.policy {
+	case Block:
+		select {
+		case s.c <- e:
+		case <-s.done:
+		}
+	case DropNewest:
+		select {
+		case s.c <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	case DropOldest:
+		select {
+		case s.c <- e:
+			return
+		default:
+		}
+		select {
+		case <-s.c:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+		select {
+		case s.c <- e:
+		default:
+			// still no room, as with unbuffered channels or concurrent dispatching
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
+
+// Dispatcher separates the events pushed by the daemon from request/response traffic
+// and delivers them to all interested subscribers.
+type Dispatcher struct {
+	mu   sync.RWMutex
+	subs []*Subscription
+}
+
+// NewDispatcher returns a dispatcher without subscriptions
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// Subscribe returns a subscription delivering events on a channel with the specified buffer size,
+// using the specified policy when the channel is full.
+// Only the events with the specified names are delivered; if none is specified, all events are delivered.
+func (d *Dispatcher) Subscribe(size int, policy Backpressure, names ...string) *Subscription {
+	c := make(chan Event, size)
+	s := &Subscription{C: c, c: c, policy: policy}
+	d.add(s, names)
+	return s
+}
+
+// Handle returns a subscription invoking fn for every event with one of the specified names, or for
+// all events if none is specified. The callback is invoked synchronously by Dispatch.
+func (d *Dispatcher) Handle(fn func(Event), names ...string) *Subscription {
+	s := &Subscription{fn: fn}
+	d.add(s, names)
+	return s
+}
+
+func (d *Dispatcher) add(s *Subscription, names []string) {
+	s.d = d
+	s.done = make(chan struct{})
+	if len(names) != 0 {
+		s.names = map[string]bool{}
+		for _, name := range names {
+			s.names[name] = true
+		}
+	}
+
+	d.mu.Lock()
+	d.subs = append(d.subs, s)
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) remove(s *Subscription) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, sub := range d.subs {
+		if sub == s {
+			d.subs = append(d.subs[:i], d.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Interests returns the sorted names of the events subscribed so far, suitable for 'daemon.set_event_interest';
+// if any subscription accepts all events, the names of all known events are returned.
+func (d *Dispatcher) Interests() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	names := map[string]bool{}
+	for _, s := range d.subs {
+		if len(s.names) == 0 {
+			for name := range eventParsers {
+				names[name] = true
+			}
+			continue
+		}
+		for name := range s.names {
+			names[name] = true
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Publish delivers an event to all interested subscribers
+func (d *Dispatcher) Publish(e Event) {
+	d.mu.RLock()
+	subs := make([]*Subscription, len(d.subs))
+	copy(subs, d.subs)
+	d.mu.RUnlock()
+
+	for _, s := range subs {
+		if s.wants(e) {
+			s.deliver(e)
+		}
+	}
+}
+
+// Dispatch inspects a message received from the daemon; if it is an event, it is delivered
+// to the subscribers and true is returned. Any other message is left to the caller.
+func (d *Dispatcher) Dispatch(msg rencode.List) (bool, error) {
+	var msgType int
+	err := msg.Scan(&msgType)
+	if err != nil {
+		return false, fmt.Errorf("invalid message: %v", err)
+	}
+	if msgType != RPCEvent {
+		return false, nil
+	}
+
+	e, err := ParseEventMessage(msg)
+	if err != nil {
+		return true, err
+	}
+	d.Publish(e)
+	return true, nil
+}
+
+// Run decodes messages from the specified decoder until an error occurs; events are dispatched
+// to the subscribers while any other message is passed to the 'other' function.
+func (d *Dispatcher) Run(dec *rencode.Decoder, other func(msg rencode.List)) error {
+	for {
+		var msg rencode.List
+		err := dec.Scan(&msg)
+		if err != nil {
+			return err
+		}
+
+		handled, err := d.Dispatch(msg)
+		if err != nil {
+			return err
+		}
+		if !handled {
+			other(msg)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package deluge
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestParseEventMessage(t *testing.T) {
+	t.Parallel()
+
+	msg := rencode.NewList(RPCEvent, []byte("TorrentStateChangedEvent"), rencode.NewList([]byte("abcdef"), []byte("Seeding")))
+
+	e, err := ParseEventMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc, ok := e.(TorrentStateChangedEvent)
+	if !ok {
+		t.Fatalf("expected TorrentStateChangedEvent but %T found", e)
+	}
+	if sc.TorrentID != "abcdef" || sc.State != "Seeding" {
+		t.Errorf("unexpected event %+v", sc)
+	}
+
+	e, err = ParseEvent("SomePluginEvent", rencode.NewList(int8(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := e.(UnknownEvent); !ok || e.EventName() != "SomePluginEvent" {
+		t.Errorf("expected unknown event but %#v found", e)
+	}
+}
+
+func TestDispatcherRun(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	e := rencode.NewEncoder(&b)
+	err := e.Encode(
+		rencode.NewList(RPCEvent, "TorrentAddedEvent", rencode.NewList("abcdef", true)),
+		rencode.NewList(RPCResponse, 1, "2.0.3"),
+		rencode.NewList(RPCEvent, "TorrentFinishedEvent", rencode.NewList("abcdef")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDispatcher()
+	s := d.Subscribe(10, Block, "TorrentFinishedEvent")
+	var handled []Event
+	d.Handle(func(e Event) {
+		handled = append(handled, e)
+	})
+
+	var others []rencode.List
+	err = d.Run(rencode.NewDecoder(&b), func(msg rencode.List) {
+		others = append(others, msg)
+	})
+	if err != io.EOF {
+		t.Fatal(err)
+	}
+
+	if len(others) != 1 {
+		t.Errorf("expected 1 response but %d found", len(others))
+	}
+	if len(handled) != 2 {
+		t.Errorf("expected 2 events delivered to callback but %d found", len(handled))
+	}
+	if len(s.C) != 1 {
+		t.Fatalf("expected 1 event delivered to channel but %d found", len(s.C))
+	}
+	if _, ok := (<-s.C).(TorrentFinishedEvent); !ok {
+		t.Error("expected TorrentFinishedEvent")
+	}
+}
+
+func TestBackpressure(t *testing.T) {
+	t.Parallel()
+
+	d := NewDispatcher()
+	newest := d.Subscribe(1, DropNewest)
+	oldest := d.Subscribe(1, DropOldest)
+
+	d.Publish(TorrentRemovedEvent{"first"})
+	d.Publish(TorrentRemovedEvent{"second"})
+
+	if newest.Dropped() != 1 || oldest.Dropped() != 1 {
+		t.Fatalf("expected one dropped event per subscription, got %d and %d", newest.Dropped(), oldest.Dropped())
+	}
+	if e := (<-newest.C).(TorrentRemovedEvent); e.TorrentID != "first" {
+		t.Errorf("expected newest event to be dropped, got %v", e)
+	}
+	if e := (<-oldest.C).(TorrentRemovedEvent); e.TorrentID != "second" {
+		t.Errorf("expected oldest event to be dropped, got %v", e)
+	}
+
+	newest.Close()
+	if _, ok := <-newest.C; ok {
+		t.Error("expected channel to be closed")
+	}
+	if len(d.Interests()) != len(eventParsers) {
+		t.Errorf("expected all events to be of interest")
+	}
+}
+
+func TestDropOldestUnbuffered(t *testing.T) {
+	t.Parallel()
+
+	d := NewDispatcher()
+	s := d.Subscribe(0, DropOldest)
+	defer s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Publish(TorrentRemovedEvent{"first"})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publishing to an unbuffered subscription without receiver did not return")
+	}
+	if s.Dropped() != 1 {
+		t.Errorf("expected one dropped event, got %d", s.Dropped())
+	}
+}
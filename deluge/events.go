@@ -0,0 +1,285 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package deluge
+
+import (
+	"fmt"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// Message types as defined in deluge/core/rpcserver.py
+const (
+	RPCResponse = 1
+	RPCError    = 2
+	RPCEvent    = 3
+)
+
+// Event is implemented by all the events pushed by the daemon
+type Event interface {
+	EventName() string
+}
+
+// TorrentAddedEvent is emitted when a new torrent is successfully added to the session
+type TorrentAddedEvent struct {
+	TorrentID string
+	FromState bool
+}
+
+// TorrentRemovedEvent is emitted when a torrent has been removed from the session
+type TorrentRemovedEvent struct {
+	TorrentID string
+}
+
+// PreTorrentRemovedEvent is emitted when a torrent is about to be removed from the session
+type PreTorrentRemovedEvent struct {
+	TorrentID string
+}
+
+// TorrentStateChangedEvent is emitted whenever a torrent changes state
+type TorrentStateChangedEvent struct {
+	TorrentID string
+	State     string
+}
+
+// TorrentQueueChangedEvent is emitted when the queue order has changed
+type TorrentQueueChangedEvent struct{}
+
+// TorrentFolderRenamedEvent is emitted when a folder within a torrent has been renamed
+type TorrentFolderRenamedEvent struct {
+	TorrentID string
+	Old       string
+	New       string
+}
+
+// TorrentFileRenamedEvent is emitted when a file within a torrent has been renamed
+type TorrentFileRenamedEvent struct {
+	TorrentID string
+	Index     int
+	Name      string
+}
+
+// TorrentFinishedEvent is emitted when a torrent finishes downloading
+type TorrentFinishedEvent struct {
+	TorrentID string
+}
+
+// TorrentResumedEvent is emitted when a torrent resumes from a paused state
+type TorrentResumedEvent struct {
+	TorrentID string
+}
+
+// NewVersionAvailableEvent is emitted when a more recent version of Deluge is available
+type NewVersionAvailableEvent struct {
+	NewRelease string
+}
+
+// SessionPausedEvent is emitted when the session has been paused
+type SessionPausedEvent struct{}
+
+// SessionResumedEvent is emitted when the session has been resumed
+type SessionResumedEvent struct{}
+
+// ConfigValueChangedEvent is emitted when a config value changes in the core
+type ConfigValueChangedEvent struct {
+	Key   string
+	Value interface{}
+}
+
+// PluginEnabledEvent is emitted when a plugin is enabled in the core
+type PluginEnabledEvent struct {
+	PluginName string
+}
+
+// PluginDisabledEvent is emitted when a plugin is disabled in the core
+type PluginDisabledEvent struct {
+	PluginName string
+}
+
+// UnknownEvent holds any event which has no typed mapping
+type UnknownEvent struct {
+	Name string
+	Args rencode.List
+}
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentAddedEvent) EventName() string { return "TorrentAddedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentRemovedEvent) EventName() string { return "TorrentRemovedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (PreTorrentRemovedEvent) EventName() string { return "PreTorrentRemovedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentStateChangedEvent) EventName() string { return "TorrentStateChangedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentQueueChangedEvent) EventName() string { return "TorrentQueueChangedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentFolderRenamedEvent) EventName() string { return "TorrentFolderRenamedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentFileRenamedEvent) EventName() string { return "TorrentFileRenamedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentFinishedEvent) EventName() string { return "TorrentFinishedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (TorrentResumedEvent) EventName() string { return "TorrentResumedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (NewVersionAvailableEvent) EventName() string { return "NewVersionAvailableEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (SessionPausedEvent) EventName() string { return "SessionPausedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (SessionResumedEvent) EventName() string { return "SessionResumedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (ConfigValueChangedEvent) EventName() string { return "ConfigValueChangedEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (PluginEnabledEvent) EventName() string { return "PluginEnabledEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (PluginDisabledEvent) EventName() string { return "PluginDisabledEvent" }
+
+// EventName returns the name of the event as sent by the daemon
+func (e UnknownEvent) EventName() string { return e.Name }
+
+// eventParsers maps each known event name to the function unpacking its arguments
+var eventParsers = map[string]func(args rencode.List) (Event, error){
+	"TorrentAddedEvent": func(args rencode.List) (Event, error) {
+		var e TorrentAddedEvent
+		// 'from_state' is not sent by Deluge 1.3
+		if args.Length() < 2 {
+			err := args.Scan(&e.TorrentID)
+			return e, err
+		}
+		err := args.Scan(&e.TorrentID, &e.FromState)
+		return e, err
+	},
+	"TorrentRemovedEvent": func(args rencode.List) (Event, error) {
+		var e TorrentRemovedEvent
+		err := args.Scan(&e.TorrentID)
+		return e, err
+	},
+	"PreTorrentRemovedEvent": func(args rencode.List) (Event, error) {
+		var e PreTorrentRemovedEvent
+		err := args.Scan(&e.TorrentID)
+		return e, err
+	},
+	"TorrentStateChangedEvent": func(args rencode.List) (Event, error) {
+		var e TorrentStateChangedEvent
+		err := args.Scan(&e.TorrentID, &e.State)
+		return e, err
+	},
+	"TorrentQueueChangedEvent": func(args rencode.List) (Event, error) {
+		return TorrentQueueChangedEvent{}, nil
+	},
+	"TorrentFolderRenamedEvent": func(args rencode.List) (Event, error) {
+		var e TorrentFolderRenamedEvent
+		err := args.Scan(&e.TorrentID, &e.Old, &e.New)
+		return e, err
+	},
+	"TorrentFileRenamedEvent": func(args rencode.List) (Event, error) {
+		var e TorrentFileRenamedEvent
+		err := args.Scan(&e.TorrentID, &e.Index, &e.Name)
+		return e, err
+	},
+	"TorrentFinishedEvent": func(args rencode.List) (Event, error) {
+		var e TorrentFinishedEvent
+		err := args.Scan(&e.TorrentID)
+		return e, err
+	},
+	"TorrentResumedEvent": func(args rencode.List) (Event, error) {
+		var e TorrentResumedEvent
+		err := args.Scan(&e.TorrentID)
+		return e, err
+	},
+	"NewVersionAvailableEvent": func(args rencode.List) (Event, error) {
+		var e NewVersionAvailableEvent
+		err := args.Scan(&e.NewRelease)
+		return e, err
+	},
+	"SessionPausedEvent": func(args rencode.List) (Event, error) {
+		return SessionPausedEvent{}, nil
+	},
+	"SessionResumedEvent": func(args rencode.List) (Event, error) {
+		return SessionResumedEvent{}, nil
+	},
+	"ConfigValueChangedEvent": func(args rencode.List) (Event, error) {
+		var e ConfigValueChangedEvent
+		err := args.Scan(&e.Key)
+		if err != nil {
+			return e, err
+		}
+		if args.Length() > 1 {
+			e.Value = args.Values()[1]
+		}
+		return e, nil
+	},
+	"PluginEnabledEvent": func(args rencode.List) (Event, error) {
+		var e PluginEnabledEvent
+		err := args.Scan(&e.PluginName)
+		return e, err
+	},
+	"PluginDisabledEvent": func(args rencode.List) (Event, error) {
+		var e PluginDisabledEvent
+		err := args.Scan(&e.PluginName)
+		return e, err
+	},
+}
+
+// ParseEvent maps the event name and arguments sent by the daemon to the corresponding typed event;
+// events which are not known are returned as UnknownEvent.
+func ParseEvent(name string, args rencode.List) (Event, error) {
+	parse, ok := eventParsers[name]
+	if !ok {
+		return UnknownEvent{name, args}, nil
+	}
+
+	e, err := parse(args)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %v", name, err)
+	}
+	return e, nil
+}
+
+// ParseEventMessage parses a message of type RPCEvent, as received from the daemon
+func ParseEventMessage(msg rencode.List) (Event, error) {
+	var (
+		msgType int
+		name    string
+		args    rencode.List
+	)
+	err := msg.Scan(&msgType, &name, &args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event message: %v", err)
+	}
+	if msgType != RPCEvent {
+		return nil, fmt.Errorf("message of type %d is not an event", msgType)
+	}
+
+	return ParseEvent(name, args)
+}
@@ -0,0 +1,223 @@
+// +build generate
+
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// template block starts
+const top = `//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+// Code generated by generate.go from methods.spec; DO NOT EDIT.
+
+package deluge
+
+import (
+	"context"
+
+	"github.com/gdm85/go-rencode"
+)
+`
+
+// template block ends
+
+type arg struct {
+	name, typ string
+}
+
+type method struct {
+	namespace, name string
+	args            []arg
+	result          string
+}
+
+var methodLine = regexp.MustCompile(`^([a-z_]+)\.([a-z_]+)\((.*)\)\s*(.*)$`)
+
+// goName converts a 'snake_case' identifier to the corresponding 'CamelCase' representation.
+func goName(s string, exported bool) string {
+	parts := strings.Split(s, "_")
+	for i, p := range parts {
+		if p == "" || (i == 0 && !exported) {
+			continue
+		}
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func parseSpec(path string) ([]method, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var methods []method
+	s := bufio.NewScanner(f)
+	for lineNo := 1; s.Scan(); lineNo++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		m := methodLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("%s:%d: invalid method declaration", path, lineNo)
+		}
+
+		decl := method{namespace: m[1], name: m[2], result: m[4]}
+		if m[3] != "" {
+			for _, a := range strings.Split(m[3], ",") {
+				fields := strings.Fields(a)
+				if len(fields) != 2 {
+					return nil, fmt.Errorf("%s:%d: invalid argument %q", path, lineNo, a)
+				}
+				decl.args = append(decl.args, arg{fields[0], fields[1]})
+			}
+		}
+		methods = append(methods, decl)
+	}
+
+	return methods, s.Err()
+}
+
+func generate(b *bytes.Buffer, methods []method) {
+	fmt.Fprintln(b, top)
+
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, m := range methods {
+		if !seen[m.namespace] {
+			seen[m.namespace] = true
+			namespaces = append(namespaces, m.namespace)
+		}
+	}
+
+	for _, ns := range namespaces {
+		t := goName(ns, true)
+		fmt.Fprintf(b, `// %s exposes the methods of the %q RPC namespace
+type %s struct {
+	caller Caller
+}
+
+// %s returns the bindings for the %q RPC namespace
+func (c *Client) %s() %s {
+	return %s{c.caller}
+}
+`, t, ns, t, t, ns, t, t, t)
+	}
+
+	for _, m := range methods {
+		var params, values []string
+		for _, a := range m.args {
+			n := goName(a.name, false)
+			params = append(params, n+" "+a.typ)
+			values = append(values, "toValue("+n+")")
+		}
+
+		rpcName := m.namespace + "." + m.name
+		fmt.Fprintf(b, "\n// %s calls the %q RPC method\n", goName(m.name, true), rpcName)
+		fmt.Fprintf(b, "func (n %s) %s(%s) ", goName(m.namespace, true), goName(m.name, true),
+			strings.Join(append([]string{"ctx context.Context"}, params...), ", "))
+
+		if m.result == "" {
+			fmt.Fprintf(b, `error {
+	_, err := n.caller.Call(ctx, %q, rencode.NewList(%s), rencode.Dictionary{})
+	return err
+}
+`, rpcName, strings.Join(values, ", "))
+			continue
+		}
+
+		fmt.Fprintf(b, `(%s, error) {
+	var result %s
+	v, err := n.caller.Call(ctx, %q, rencode.NewList(%s), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+`, m.result, m.result, rpcName, strings.Join(values, ", "))
+	}
+}
+
+func main() {
+	output := flag.String("o", "", "output file (default is standard output)")
+	flag.Parse()
+
+	spec := "methods.spec"
+	if flag.NArg() > 0 {
+		spec = flag.Arg(0)
+	}
+
+	methods, err := parseSpec(spec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var b bytes.Buffer
+	generate(&b, methods)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "generated invalid code:", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	err = ioutil.WriteFile(*output, src, 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
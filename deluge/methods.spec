@@ -0,0 +1,35 @@
+# Deluge RPC method specification; see generate.go for the syntax.
+#
+# Each non-empty line declares one method as:
+#
+#	namespace.method_name(arg_name type, ...) result_type
+#
+# Types are Go types as seen from package deluge; the result type can be
+# omitted for methods whose result is not meaningful.
+
+daemon.login(username string, password string) int
+daemon.info() string
+daemon.get_method_list() []string
+daemon.set_event_interest(events []string) bool
+
+core.get_torrents_status(filter rencode.Dictionary, keys []string) map[string]TorrentStatus
+core.get_torrent_status(torrent_id string, keys []string) TorrentStatus
+core.get_session_state() []string
+core.get_config_value(key string) interface{}
+core.set_config(config rencode.Dictionary)
+core.get_free_space(path string) int64
+core.get_libtorrent_version() string
+core.add_torrent_magnet(uri string, options rencode.Dictionary) string
+core.add_torrent_url(url string, options rencode.Dictionary) string
+core.remove_torrent(torrent_id string, remove_data bool) bool
+core.pause_torrent(torrent_ids []string)
+core.resume_torrent(torrent_ids []string)
+core.get_available_plugins() []string
+core.get_enabled_plugins() []string
+core.enable_plugin(plugin string)
+core.disable_plugin(plugin string)
+
+label.get_labels() []string
+label.add(label_id string)
+label.remove(label_id string)
+label.set_torrent(torrent_id string, label_id string)
@@ -0,0 +1,282 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+// Code generated by generate.go from methods.spec; DO NOT EDIT.
+
+package deluge
+
+import (
+	"context"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// Daemon exposes the methods of the "daemon" RPC namespace
+type Daemon struct {
+	caller Caller
+}
+
+// Daemon returns the bindings for the "daemon" RPC namespace
+func (c *Client) Daemon() Daemon {
+	return Daemon{c.caller}
+}
+
+// Core exposes the methods of the "core" RPC namespace
+type Core struct {
+	caller Caller
+}
+
+// Core returns the bindings for the "core" RPC namespace
+func (c *Client) Core() Core {
+	return Core{c.caller}
+}
+
+// Label exposes the methods of the "label" RPC namespace
+type Label struct {
+	caller Caller
+}
+
+// Label returns the bindings for the "label" RPC namespace
+func (c *Client) Label() Label {
+	return Label{c.caller}
+}
+
+// Login calls the "daemon.login" RPC method
+func (n Daemon) Login(ctx context.Context, username string, password string) (int, error) {
+	var result int
+	v, err := n.caller.Call(ctx, "daemon.login", rencode.NewList(toValue(username), toValue(password)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// Info calls the "daemon.info" RPC method
+func (n Daemon) Info(ctx context.Context) (string, error) {
+	var result string
+	v, err := n.caller.Call(ctx, "daemon.info", rencode.NewList(), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// GetMethodList calls the "daemon.get_method_list" RPC method
+func (n Daemon) GetMethodList(ctx context.Context) ([]string, error) {
+	var result []string
+	v, err := n.caller.Call(ctx, "daemon.get_method_list", rencode.NewList(), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// SetEventInterest calls the "daemon.set_event_interest" RPC method
+func (n Daemon) SetEventInterest(ctx context.Context, events []string) (bool, error) {
+	var result bool
+	v, err := n.caller.Call(ctx, "daemon.set_event_interest", rencode.NewList(toValue(events)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// GetTorrentsStatus calls the "core.get_torrents_status" RPC method
+func (n Core) GetTorrentsStatus(ctx context.Context, filter rencode.Dictionary, keys []string) (map[string]TorrentStatus, error) {
+	var result map[string]TorrentStatus
+	v, err := n.caller.Call(ctx, "core.get_torrents_status", rencode.NewList(toValue(filter), toValue(keys)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// GetTorrentStatus calls the "core.get_torrent_status" RPC method
+func (n Core) GetTorrentStatus(ctx context.Context, torrentId string, keys []string) (TorrentStatus, error) {
+	var result TorrentStatus
+	v, err := n.caller.Call(ctx, "core.get_torrent_status", rencode.NewList(toValue(torrentId), toValue(keys)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// GetSessionState calls the "core.get_session_state" RPC method
+func (n Core) GetSessionState(ctx context.Context) ([]string, error) {
+	var result []string
+	v, err := n.caller.Call(ctx, "core.get_session_state", rencode.NewList(), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// GetConfigValue calls the "core.get_config_value" RPC method
+func (n Core) GetConfigValue(ctx context.Context, key string) (interface{}, error) {
+	var result interface{}
+	v, err := n.caller.Call(ctx, "core.get_config_value", rencode.NewList(toValue(key)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// SetConfig calls the "core.set_config" RPC method
+func (n Core) SetConfig(ctx context.Context, config rencode.Dictionary) error {
+	_, err := n.caller.Call(ctx, "core.set_config", rencode.NewList(toValue(config)), rencode.Dictionary{})
+	return err
+}
+
+// GetFreeSpace calls the "core.get_free_space" RPC method
+func (n Core) GetFreeSpace(ctx context.Context, path string) (int64, error) {
+	var result int64
+	v, err := n.caller.Call(ctx, "core.get_free_space", rencode.NewList(toValue(path)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// GetLibtorrentVersion calls the "core.get_libtorrent_version" RPC method
+func (n Core) GetLibtorrentVersion(ctx context.Context) (string, error) {
+	var result string
+	v, err := n.caller.Call(ctx, "core.get_libtorrent_version", rencode.NewList(), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// AddTorrentMagnet calls the "core.add_torrent_magnet" RPC method
+func (n Core) AddTorrentMagnet(ctx context.Context, uri string, options rencode.Dictionary) (string, error) {
+	var result string
+	v, err := n.caller.Call(ctx, "core.add_torrent_magnet", rencode.NewList(toValue(uri), toValue(options)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// AddTorrentUrl calls the "core.add_torrent_url" RPC method
+func (n Core) AddTorrentUrl(ctx context.Context, url string, options rencode.Dictionary) (string, error) {
+	var result string
+	v, err := n.caller.Call(ctx, "core.add_torrent_url", rencode.NewList(toValue(url), toValue(options)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// RemoveTorrent calls the "core.remove_torrent" RPC method
+func (n Core) RemoveTorrent(ctx context.Context, torrentId string, removeData bool) (bool, error) {
+	var result bool
+	v, err := n.caller.Call(ctx, "core.remove_torrent", rencode.NewList(toValue(torrentId), toValue(removeData)), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// PauseTorrent calls the "core.pause_torrent" RPC method
+func (n Core) PauseTorrent(ctx context.Context, torrentIds []string) error {
+	_, err := n.caller.Call(ctx, "core.pause_torrent", rencode.NewList(toValue(torrentIds)), rencode.Dictionary{})
+	return err
+}
+
+// ResumeTorrent calls the "core.resume_torrent" RPC method
+func (n Core) ResumeTorrent(ctx context.Context, torrentIds []string) error {
+	_, err := n.caller.Call(ctx, "core.resume_torrent", rencode.NewList(toValue(torrentIds)), rencode.Dictionary{})
+	return err
+}
+
+// GetAvailablePlugins calls the "core.get_available_plugins" RPC method
+func (n Core) GetAvailablePlugins(ctx context.Context) ([]string, error) {
+	var result []string
+	v, err := n.caller.Call(ctx, "core.get_available_plugins", rencode.NewList(), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// GetEnabledPlugins calls the "core.get_enabled_plugins" RPC method
+func (n Core) GetEnabledPlugins(ctx context.Context) ([]string, error) {
+	var result []string
+	v, err := n.caller.Call(ctx, "core.get_enabled_plugins", rencode.NewList(), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// EnablePlugin calls the "core.enable_plugin" RPC method
+func (n Core) EnablePlugin(ctx context.Context, plugin string) error {
+	_, err := n.caller.Call(ctx, "core.enable_plugin", rencode.NewList(toValue(plugin)), rencode.Dictionary{})
+	return err
+}
+
+// DisablePlugin calls the "core.disable_plugin" RPC method
+func (n Core) DisablePlugin(ctx context.Context, plugin string) error {
+	_, err := n.caller.Call(ctx, "core.disable_plugin", rencode.NewList(toValue(plugin)), rencode.Dictionary{})
+	return err
+}
+
+// GetLabels calls the "label.get_labels" RPC method
+func (n Label) GetLabels(ctx context.Context) ([]string, error) {
+	var result []string
+	v, err := n.caller.Call(ctx, "label.get_labels", rencode.NewList(), rencode.Dictionary{})
+	if err != nil {
+		return result, err
+	}
+	err = fromValue(v, &result)
+	return result, err
+}
+
+// Add calls the "label.add" RPC method
+func (n Label) Add(ctx context.Context, labelId string) error {
+	_, err := n.caller.Call(ctx, "label.add", rencode.NewList(toValue(labelId)), rencode.Dictionary{})
+	return err
+}
+
+// Remove calls the "label.remove" RPC method
+func (n Label) Remove(ctx context.Context, labelId string) error {
+	_, err := n.caller.Call(ctx, "label.remove", rencode.NewList(toValue(labelId)), rencode.Dictionary{})
+	return err
+}
+
+// SetTorrent calls the "label.set_torrent" RPC method
+func (n Label) SetTorrent(ctx context.Context, torrentId string, labelId string) error {
+	_, err := n.caller.Call(ctx, "label.set_torrent", rencode.NewList(toValue(torrentId), toValue(labelId)), rencode.Dictionary{})
+	return err
+}
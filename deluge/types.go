@@ -0,0 +1,57 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package deluge
+
+// TorrentStatus is the status of a single torrent as returned by 'core.get_torrent_status'
+// and 'core.get_torrents_status'; the keys argument of these methods must be TorrentStatusKeys.
+type TorrentStatus struct {
+	Hash                string
+	Name                string
+	State               string
+	Progress            float32
+	Ratio               float32
+	TotalSize           int64
+	TotalDone           int64
+	SavePath            string
+	ETA                 int64
+	TimeAdded           float32
+	NumPeers            int64
+	NumSeeds            int64
+	DownloadPayloadRate int64
+	UploadPayloadRate   int64
+}
+
+// TorrentStatusKeys are the status keys that map to the fields of TorrentStatus
+var TorrentStatusKeys = []string{
+	"hash",
+	"name",
+	"state",
+	"progress",
+	"ratio",
+	"total_size",
+	"total_done",
+	"save_path",
+	"eta",
+	"time_added",
+	"num_peers",
+	"num_seeds",
+	"download_payload_rate",
+	"upload_payload_rate",
+}
@@ -22,20 +22,129 @@ package rencode
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"unicode"
 )
 
 var (
 	// ErrKeyAlreadyExists is the error returned when the specified key is already defined within the dictionary
 	ErrKeyAlreadyExists = errors.New("key already exists in dictionary")
+	// ErrMissingField is the error of a struct field without a corresponding dictionary key
+	ErrMissingField = errors.New("no value for field")
+	// ErrUnknownKey is the error of a dictionary key which does not map to any struct field
+	ErrUnknownKey = errors.New("no field for key")
 )
 
-// Dictionary is a rencode-specific dictionary that allows any type of key to be mapped to any type of value
+// MergePolicy specifies how Merge handles keys which exist in both dictionaries
+type MergePolicy int
+
+const (
+	// MergeOverwrite replaces the existing values with the values of the other dictionary
+	MergeOverwrite MergePolicy = iota
+	// MergeKeep keeps the existing values
+	MergeKeep
+	// MergeError fails with ErrKeyAlreadyExists without modifying the dictionary
+	MergeError
+)
+
+// Dictionary is a rencode-specific dictionary that allows any type of key to be mapped to any type of value.
+// Like a slice, a copy of a dictionary shares its storage with the original and the methods modify it
+// in place, so only one of them can be modified; use Clone to obtain an independent dictionary.
+// Lookups in dictionaries with many keys build a hash index, so reflect.DeepEqual can report
+// dictionaries with the same pairs as different; compare them with Equal instead.
 type Dictionary struct {
 	values []interface{}
 	keys   []interface{}
+	// index is allocated by the methods adding keys, once the dictionary has enough keys
+	index *dictionaryIndex
+}
+
+// dictionaryIndexThreshold is the count of keys from which a hash index is used for lookups
+const dictionaryIndexThreshold = 16
+
+// dictionaryIndex maps the normalized keys of a dictionary to the position of their first occurrence;
+// it is built by the first lookup and shared by the copies of the dictionary, which use it as long
+// as it has been built for their keys.
+type dictionaryIndex struct {
+	mu sync.Mutex
+	// first and length identify the keys the positions have been built for
+	first     *interface{}
+	length    int
+	positions map[interface{}]int
+}
+
+// indexKey returns the key as stored in a dictionaryIndex; byte slices are normalized to strings
+// and only keys of scalar types can be indexed.
+func indexKey(key interface{}) (interface{}, bool) {
+	switch k := key.(type) {
+	case []byte:
+		return string(k), true
+	case nil, string, bool, int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint, float32, float64:
+		return k, true
+	}
+	return nil, false
+}
+
+func (index *dictionaryIndex) add(key interface{}, i int) {
+	k, ok := indexKey(key)
+	if !ok {
+		return
+	}
+	if _, ok := index.positions[k]; !ok {
+		index.positions[k] = i
+	}
+}
+
+// lookup returns the position of the normalized key k within keys, building the index on first use;
+// ok is false if the index has been built for the keys of another copy of the dictionary.
+func (index *dictionaryIndex) lookup(keys []interface{}, k interface{}) (i int, ok bool) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if index.positions == nil {
+		index.first, index.length = &keys[0], len(keys)
+		index.positions = make(map[interface{}]int, len(keys))
+		for j, key := range keys {
+			index.add(key, j)
+		}
+	} else if index.first != &keys[0] || index.length != len(keys) {
+		return -1, false
+	}
+
+	i, ok = index.positions[k]
+	if !ok {
+		return -1, true
+	}
+	return i, true
+}
+
+// appended updates a built index after a key has been appended to keys, which had the specified
+// first element and length before; false is returned if the index was built for other keys.
+func (index *dictionaryIndex) appended(first *interface{}, length int, keys []interface{}) bool {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if index.positions == nil {
+		return true
+	}
+	if index.first != first || index.length != length {
+		return false
+	}
+	index.add(keys[length], length)
+	index.first, index.length = &keys[0], len(keys)
+	return true
+}
+
+// reindex replaces the index after the keys have been removed or reordered
+func (d *Dictionary) reindex() {
+	d.index = nil
+	if len(d.keys) >= dictionaryIndexThreshold {
+		d.index = &dictionaryIndex{}
+	}
 }
 
 // Length returns the total count of elements
@@ -55,44 +164,79 @@ func (d *Dictionary) Values() []interface{} {
 
 // Add appends a new (key, value) pair; does not check if key already exists.
 func (d *Dictionary) Add(key, value interface{}) {
+	var first *interface{}
+	if len(d.keys) != 0 {
+		first = &d.keys[0]
+	}
+	length := len(d.keys)
+
 	d.keys = append(d.keys, key)
 	d.values = append(d.values, value)
+
+	switch {
+	case d.index != nil:
+		// keep a built index up to date, unless it belongs to another copy of the dictionary
+		if !d.index.appended(first, length, d.keys) {
+			d.index = &dictionaryIndex{}
+		}
+	case len(d.keys) >= dictionaryIndexThreshold:
+		d.index = &dictionaryIndex{}
+	}
 }
 
 // Get returns the value in the dictionary corresponding to the specified key.
 // If the key is not found then 'nil, false' is returned instead.
 // Keys of type 'string' and '[]byte' are both compared as if they were strings.
+// Dictionaries with many keys build a hash index on the first lookup.
 //NOTE: slice keys cannot be used with this method.
 func (d *Dictionary) Get(key interface{}) (interface{}, bool) {
-	// normalize a byte array key to string
-	if keyAsByteArray, ok := key.([]byte); ok {
-		return d.internalGet(string(keyAsByteArray))
+	i := d.find(key)
+	if i < 0 {
+		return nil, false
 	}
-
-	// any other key type
-	// notice that here slice keys will not be treated in any particular way
-	return d.internalGet(key)
+	return d.values[i], true
 }
 
-func (d *Dictionary) internalGet(key interface{}) (interface{}, bool) {
-	for i, k := range d.keys {
-		// convert byte array keys to string
-		if kAsByteArray, ok := k.([]byte); ok {
-			k := string(kAsByteArray)
+// find returns the position of the first occurrence of the specified key, or -1 if not found
+func (d *Dictionary) find(key interface{}) int {
+	if k, ok := indexKey(key); ok && d.index != nil && len(d.keys) != 0 {
+		if i, ok := d.index.lookup(d.keys, k); ok {
+			return i
+		}
+	}
 
-			if k == key {
-				return d.values[i], true
-			}
-			continue
+	key = normalizeKey(key)
+	for i, k := range d.keys {
+		if keyMatches(k, key) {
+			return i
 		}
+	}
+
+	return -1
+}
 
+// normalizeKey converts a byte array key to string
+func normalizeKey(key interface{}) interface{} {
+	if keyAsByteArray, ok := key.([]byte); ok {
+		return string(keyAsByteArray)
+	}
+	return key
+}
+
+// keyMatches returns true if the dictionary key k matches the normalized key
+func keyMatches(k, key interface{}) bool {
+	// convert byte array keys to string
+	if kAsByteArray, ok := k.([]byte); ok {
+		return string(kAsByteArray) == key
+	}
+
+	if _, ok := indexKey(key); ok {
 		// generic inteface comparison
-		if k == key {
-			return d.values[i], true
-		}
+		return k == key
 	}
 
-	return nil, false
+	// keys like big integers, lists and tuples cannot be compared with ==
+	return Equal(k, key, EqualOptions{IgnoreBytesString: true})
 }
 
 // Zip returns a map with strings as keys or an error if a duplicate key exists.
@@ -119,6 +263,145 @@ func (d *Dictionary) Zip() (map[string]interface{}, error) {
 	return result, nil
 }
 
+// Has returns true if the specified key exists, with the same key equivalence as Get.
+func (d *Dictionary) Has(key interface{}) bool {
+	return d.find(key) >= 0
+}
+
+// Set replaces the value of the first occurrence of the specified key, or adds a new (key, value) pair
+// if the key does not exist.
+func (d *Dictionary) Set(key, value interface{}) {
+	i := d.find(key)
+	if i < 0 {
+		d.Add(key, value)
+		return
+	}
+	d.values[i] = value
+}
+
+// Delete removes all the occurrences of the specified key, including the duplicates added with Add,
+// and returns true if any was found.
+func (d *Dictionary) Delete(key interface{}) bool {
+	i := d.find(key)
+	if i < 0 {
+		return false
+	}
+
+	key = normalizeKey(key)
+	n := i
+	for j := i + 1; j < len(d.keys); j++ {
+		if keyMatches(d.keys[j], key) {
+			continue
+		}
+		d.keys[n], d.values[n] = d.keys[j], d.values[j]
+		n++
+	}
+	// clear the removed pairs, which can then be garbage collected
+	for j := n; j < len(d.keys); j++ {
+		d.keys[j], d.values[j] = nil, nil
+	}
+	d.keys, d.values = d.keys[:n], d.values[:n]
+	d.reindex()
+	return true
+}
+
+// deleteAt removes the (key, value) pair at position i
+func (d *Dictionary) deleteAt(i int) {
+	n := len(d.keys) - 1
+	copy(d.keys[i:], d.keys[i+1:])
+	copy(d.values[i:], d.values[i+1:])
+	// clear the removed pair, which can then be garbage collected
+	d.keys[n], d.values[n] = nil, nil
+	d.keys, d.values = d.keys[:n], d.values[:n]
+	d.reindex()
+}
+
+// Range calls f for each (key, value) pair in insertion order, until f returns false.
+func (d *Dictionary) Range(f func(key, value interface{}) bool) {
+	for i, k := range d.keys {
+		if !f(k, d.values[i]) {
+			return
+		}
+	}
+}
+
+// Clone returns a deep copy of the dictionary; nested containers, big integers and byte slices are copied too.
+func (d *Dictionary) Clone() Dictionary {
+	var c Dictionary
+	c.keys = make([]interface{}, len(d.keys))
+	c.values = make([]interface{}, len(d.values))
+	for i, k := range d.keys {
+		c.keys[i] = cloneValue(k)
+		c.values[i] = cloneValue(d.values[i])
+	}
+	c.reindex()
+	return c
+}
+
+func cloneValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case []byte:
+		return append([]byte(nil), x...)
+	case big.Int:
+		// the digits of big integers are stored in a slice too
+		var i big.Int
+		i.Set(&x)
+		return i
+	case List:
+		l := List{make([]interface{}, len(x.values))}
+		for i, e := range x.values {
+			l.values[i] = cloneValue(e)
+		}
+		return l
+	case Tuple:
+		return Tuple{cloneValue(x.List).(List)}
+	case Set:
+		return Set{cloneValue(List{x.values}).(List).values}
+	case Dictionary:
+		return x.Clone()
+	}
+	return v
+}
+
+// Merge sets all the (key, value) pairs of the other dictionary; keys which already exist
+// are handled according to the specified policy.
+func (d *Dictionary) Merge(other *Dictionary, policy MergePolicy) error {
+	if policy == MergeError {
+		for _, k := range other.keys {
+			if d.Has(k) {
+				return ErrKeyAlreadyExists
+			}
+		}
+	}
+
+	for i, k := range other.keys {
+		if policy == MergeKeep && d.Has(k) {
+			continue
+		}
+		d.Set(k, other.values[i])
+	}
+	return nil
+}
+
+// SortKeys sorts the (key, value) pairs by key with the specified less function; the sort is stable.
+func (d *Dictionary) SortKeys(less func(a, b interface{}) bool) {
+	order := make([]int, len(d.keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(d.keys[order[i]], d.keys[order[j]])
+	})
+
+	keys := make([]interface{}, len(d.keys))
+	values := make([]interface{}, len(d.values))
+	for i, j := range order {
+		keys[i], values[i] = d.keys[j], d.values[j]
+	}
+	d.keys, d.values = keys, values
+	d.reindex()
+}
+
 // ToSnakeCase will convert a 'CamelCase' string to the corresponding 'snake_case' representation.
 // Acronyms are converted to lower-case and preceded by an underscore.
 func ToSnakeCase(s string) string {
@@ -141,105 +424,473 @@ func ToSnakeCase(s string) string {
 	return string(out)
 }
 
+// StructOptions control how ToStructWithOptions maps a Dictionary into a struct
+type StructOptions struct {
+	// ExcludeAnnotationTag skips the fields with this annotation in their rencode tag
+	ExcludeAnnotationTag string
+	// IgnoreUnknownKeys ignores the dictionary keys which do not map to any field
+	IgnoreUnknownKeys bool
+	// AllowMissing leaves the fields without a dictionary key unchanged, as if they were all optional
+	AllowMissing bool
+	// AllErrors continues after a field cannot be assigned and returns all the errors as StructErrors
+	AllErrors bool
+	// TimeFormat is the representation of time.Time and time.Duration values, unless specified by the field tag
+	TimeFormat TimeFormat
+}
+
+// FieldError is the error returned when a dictionary value cannot be mapped to a struct field
+type FieldError struct {
+	// Path is the location of the value, in the syntax accepted by ParsePath
+	Path string
+	// Type is the type of the destination, nil for unknown keys
+	Type reflect.Type
+	// Value is the rencode value, nil for missing fields
+	Value interface{}
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "."
+	}
+	switch {
+	case e.Type == nil:
+		return fmt.Sprintf("%s: %v", path, e.Err)
+	case e.Err == ErrMissingField:
+		return fmt.Sprintf("%s: expected %v, got no value", path, e.Type)
+	}
+	return fmt.Sprintf("%s: expected %v, got %T: %v", path, e.Type, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// StructErrors is the error returned by ToStructWithOptions when AllErrors is set; all elements are *FieldError
+type StructErrors []error
+
+func (e StructErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns all the field errors
+func (e StructErrors) Unwrap() []error {
+	return e
+}
+
+// fieldTag holds the comma-separated options of the rencode tag of a struct field.
+// The "remain" option makes a map or Dictionary field collect all the keys not mapped to other fields,
+// "optional" leaves the field unchanged if there is no corresponding key and "default=<value>" specifies
+// the value to use in that case, written as accepted by ParseText; it must be the last option.
+// The "time=<format>" option specifies the representation of time.Time and time.Duration values, with
+// format one of float, unix, unixnano or rfc3339 (see TimeFormat).
+// Embedded structs are flattened, unless the "noinline" option is specified, and the "inline" option
+// flattens a named struct field as well.
+// Any other option is an annotation which can be used with ExcludeAnnotationTag.
+type fieldTag struct {
+	annotations []string
+	remain      bool
+	optional    bool
+	inline      bool
+	noinline    bool
+	// timeFormat is valid if hasTimeFormat is set
+	timeFormat    TimeFormat
+	hasTimeFormat bool
+	err           error
+	hasDefault    bool
+	defaultText   string
+}
+
+func parseFieldTag(f reflect.StructField) fieldTag {
+	var ft fieldTag
+	tag := f.Tag.Get("rencode")
+	for tag != "" {
+		var option string
+		if strings.HasPrefix(tag, "default=") {
+			ft.hasDefault = true
+			ft.defaultText = strings.TrimPrefix(tag, "default=")
+			break
+		}
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			option, tag = tag[:i], tag[i+1:]
+		} else {
+			option, tag = tag, ""
+		}
+
+		switch option {
+		case "remain":
+			ft.remain = true
+		case "optional":
+			ft.optional = true
+		case "inline":
+			ft.inline = true
+		case "noinline":
+			ft.noinline = true
+		case "":
+		default:
+			if strings.HasPrefix(option, "time=") {
+				ft.timeFormat, ft.hasTimeFormat = timeFormatNames[strings.TrimPrefix(option, "time=")]
+				if !ft.hasTimeFormat {
+					ft.err = fmt.Errorf("field %q: invalid time format %q", f.Name, option)
+				}
+				continue
+			}
+			ft.annotations = append(ft.annotations, option)
+		}
+	}
+	return ft
+}
+
+func (ft fieldTag) hasAnnotation(annotation string) bool {
+	for _, a := range ft.annotations {
+		if a == annotation {
+			return true
+		}
+	}
+	return false
+}
+
 // ToStruct will map a Dictionary into a struct, recursively.
 // All dictionary keys must map to a field or an error will be returned.
 // It is possible to exclude fields with a specific annotation.
+// Besides the types supported by Scan, fields can be nested structs, maps and pointers (nil for None)
+// and slices, arrays or interface{} values; nested structs embedding Positional are decoded from lists.
 func (d *Dictionary) ToStruct(dest interface{}, excludeAnnotationTag string) error {
+	return d.ToStructWithOptions(dest, StructOptions{ExcludeAnnotationTag: excludeAnnotationTag})
+}
+
+// ToStructWithOptions is like ToStruct, with options to ignore unknown keys and missing fields.
+// The rencode tag of fields can also mark them as optional, specify a default value or
+// collect the unknown keys; see fieldTag for the syntax.
+// Errors about specific fields are returned as *FieldError, or as StructErrors when AllErrors is set.
+func (d *Dictionary) ToStructWithOptions(dest interface{}, opts StructOptions) error {
 	v := reflect.ValueOf(dest)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("expected pointer to struct, got %v", v.Type())
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected pointer to struct, got %T", dest)
 	}
 
+	sd := structDecoder{opts: opts}
+	err := sd.toStruct(*d, v.Elem(), "", opts.TimeFormat)
+	if err != nil {
+		return err
+	}
+	if len(sd.errs) != 0 {
+		return sd.errs
+	}
+	return nil
+}
+
+// structDecoder maps dictionaries into structs and keeps track of the errors
+type structDecoder struct {
+	opts StructOptions
+	errs StructErrors
+}
+
+// fail records an error about the value at path; it is returned only if the decoding must stop
+func (sd *structDecoder) fail(path string, t reflect.Type, v interface{}, err error) error {
+	fe := &FieldError{Path: path, Type: t, Value: v, Err: err}
+	if sd.opts.AllErrors {
+		sd.errs = append(sd.errs, fe)
+		return nil
+	}
+	return fe
+}
+
+// toStruct maps d into the struct iv; tf is the time format of the fields without a time tag option
+func (sd *structDecoder) toStruct(d Dictionary, iv reflect.Value, path string, tf TimeFormat) error {
 	// get a temporary map with zipped fields
 	tmp, err := d.Zip()
 	if err != nil {
-		return err
+		return sd.fail(path, iv.Type(), d, err)
 	}
 
-	iv := reflect.Indirect(v)
-	t := iv.Type()
-	l := t.NumField()
-	for i := 0; i < l; i++ {
-		f := t.Field(i)
-		// destination field
-		ivf := iv.Field(i)
-		name := ToSnakeCase(f.Name)
-
-		if excludeAnnotationTag != "" {
-			rencodeTag, ok := f.Tag.Lookup("rencode")
-			if ok {
-				tags := strings.Split(rencodeTag, ",")
-				exclude := false
-				for _, tag := range tags {
-					if tag == excludeAnnotationTag {
-						exclude = true
-						break
-					}
-				}
-				if exclude {
-					// skip this field
-					delete(tmp, name)
-					continue
-				}
-			}
+	c := cachedStructCodec(iv.Type())
+	if c.err != nil {
+		return c.err
+	}
+	var remain *structField
+	for i := range c.fields {
+		f := &c.fields[i]
+		if sd.opts.ExcludeAnnotationTag != "" && f.tag.hasAnnotation(sd.opts.ExcludeAnnotationTag) {
+			// skip this field
+			delete(tmp, f.name)
+			continue
+		}
+		if f.tag.remain {
+			remain = f
+			continue
 		}
 
+		fieldPath := path + pathKey(path, f.name)
 		// see if this field is available
-		v, ok := tmp[name]
+		v, ok := tmp[f.name]
 		if !ok {
-			return fmt.Errorf("field %q: cannot be satisfied", f.Name)
+			v, ok, err = sd.missing(f, fieldPath)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
 		}
 
-		// special behaviour for slices
-		if ivf.Kind() == reflect.Slice {
-			// get value as list
-			var l List
-			err = convertAssign(v, &l)
-			if err != nil {
-				return fmt.Errorf("slice field %q: value %v: %v", f.Name, v, err)
-			}
-			// create a new slice
-			ns := reflect.MakeSlice(ivf.Type(), l.Length(), l.Length())
-			// get element type
-			elemType := ivf.Type().Elem()
-			for i, v := range l.Values() {
-				// all pointed fields are expected to be structs
-				if elemType.Kind() == reflect.Struct {
-					d, ok := v.(Dictionary)
-					if !ok {
-						return fmt.Errorf("slice field %q: expected value to be dictionary", f.Name)
-					}
-
-					obj := reflect.New(elemType)
-
-					err = d.ToStruct(obj.Interface(), excludeAnnotationTag)
-					if err != nil {
-						return fmt.Errorf("slice field %q: %v", f.Name, err)
-					}
-
-					ns.Index(i).Set(reflect.Indirect(obj))
-				} else {
-					err = convertAssign(v, ns.Index(i).Addr().Interface())
-					if err != nil {
-						return fmt.Errorf("slice field %q: value %v: %v", f.Name, v, err)
-					}
+		err = sd.assign(v, fieldByIndex(iv, f.index, true), fieldPath, f.timeFormat(tf))
+		if err != nil {
+			return err
+		}
+
+		// start removing fields that have been used
+		delete(tmp, f.name)
+	}
+
+	if remain != nil {
+		// collect the remaining keys in their original order
+		var rest Dictionary
+		for i, k := range d.keys {
+			if _, ok := tmp[normalizeKey(k).(string)]; ok {
+				rest.Add(k, d.values[i])
+			}
+		}
+		return sd.assign(rest, fieldByIndex(iv, remain.index, true), path, remain.timeFormat(tf))
+	}
+
+	if !sd.opts.IgnoreUnknownKeys {
+		for i, k := range d.keys {
+			if _, ok := tmp[normalizeKey(k).(string)]; ok {
+				err = sd.fail(path+pathKey(path, k), nil, d.values[i], ErrUnknownKey)
+				if err != nil {
+					return err
 				}
 			}
-			ivf.Set(ns)
+		}
+	}
+
+	return nil
+}
+
+// missing returns the default value of a field which is not available; if there is no default value
+// and the field is not optional a failure is recorded.
+func (sd *structDecoder) missing(f *structField, path string) (interface{}, bool, error) {
+	switch {
+	case f.tag.hasDefault:
+		if f.defaultErr != nil {
+			return nil, false, f.defaultErr
+		}
+		return cloneValue(f.defaultValue), true, nil
+	case f.tag.optional || sd.opts.AllowMissing:
+		return nil, false, nil
+	}
+	return nil, false, sd.fail(path, f.typ, nil, ErrMissingField)
+}
+
+// toTuple maps the elements of l by position into the struct iv, like toStruct; the field tagged as
+// remain collects the elements after the last field, otherwise they are unknown.
+func (sd *structDecoder) toTuple(l List, iv reflect.Value, path string, tf TimeFormat) error {
+	c := cachedStructCodec(iv.Type())
+	if c.err != nil {
+		return c.err
+	}
+	var remain *structField
+	n := 0
+	for i := range c.fields {
+		f := &c.fields[i]
+		if sd.opts.ExcludeAnnotationTag != "" && f.tag.hasAnnotation(sd.opts.ExcludeAnnotationTag) {
+			continue
+		}
+		if f.tag.remain {
+			remain = f
+			continue
+		}
+
+		fieldPath := fmt.Sprintf("%s[%d]", path, n)
+		var v interface{}
+		if n < len(l.values) {
+			v = l.values[n]
+			n++
+			if v == nil && f.tag.optional {
+				continue
+			}
 		} else {
-			err = convertAssign(v, ivf.Addr().Interface())
+			var ok bool
+			var err error
+			v, ok, err = sd.missing(f, fieldPath)
 			if err != nil {
-				return fmt.Errorf("field %q: value %v: %v", f.Name, v, err)
+				return err
+			}
+			if !ok {
+				continue
 			}
 		}
 
-		// start removing fields that have been used
-		delete(tmp, name)
+		err := sd.assign(v, fieldByIndex(iv, f.index, true), fieldPath, f.timeFormat(tf))
+		if err != nil {
+			return err
+		}
 	}
 
-	if len(tmp) != 0 {
-		return fmt.Errorf("%d fields left after parsing: %v", len(tmp), tmp)
+	if remain != nil {
+		rest := NewList(append([]interface{}(nil), l.values[n:]...)...)
+		return sd.assign(rest, fieldByIndex(iv, remain.index, true), path, remain.timeFormat(tf))
 	}
 
+	if !sd.opts.IgnoreUnknownKeys {
+		for i := n; i < len(l.values); i++ {
+			err := sd.fail(fmt.Sprintf("%s[%d]", path, i), nil, l.values[i], ErrUnknownKey)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+var (
+	listType       = reflect.TypeOf(List{})
+	dictionaryType = reflect.TypeOf(Dictionary{})
+	bigIntType     = reflect.TypeOf(big.Int{})
+	bytesType      = reflect.TypeOf([]byte(nil))
+
+	// scalarKinds are the predeclared types of each scalar kind
+	scalarKinds = map[reflect.Kind]reflect.Type{
+		reflect.Bool:    reflect.TypeOf(false),
+		reflect.Int:     reflect.TypeOf(int(0)),
+		reflect.Int8:    reflect.TypeOf(int8(0)),
+		reflect.Int16:   reflect.TypeOf(int16(0)),
+		reflect.Int32:   reflect.TypeOf(int32(0)),
+		reflect.Int64:   reflect.TypeOf(int64(0)),
+		reflect.Uint:    reflect.TypeOf(uint(0)),
+		reflect.Uint8:   reflect.TypeOf(uint8(0)),
+		reflect.Uint16:  reflect.TypeOf(uint16(0)),
+		reflect.Uint32:  reflect.TypeOf(uint32(0)),
+		reflect.Uint64:  reflect.TypeOf(uint64(0)),
+		reflect.Float32: reflect.TypeOf(float32(0)),
+		reflect.Float64: reflect.TypeOf(float64(0)),
+		reflect.String:  reflect.TypeOf(""),
+	}
+)
+
+// assign stores the value v found at path into dest, which must be settable;
+// time values are decoded with the specified format
+func (sd *structDecoder) assign(v interface{}, dest reflect.Value, path string, tf TimeFormat) error {
+	switch dest.Type() {
+	case timeType, durationType:
+		_, err := assignTime(v, dest.Addr().Interface(), tf)
+		if err != nil {
+			return sd.fail(path, dest.Type(), v, err)
+		}
+		return nil
+	case listType, dictionaryType, bigIntType, bytesType, tupleType, setType:
+		err := convertAssign(v, dest.Addr().Interface())
+		if err != nil {
+			return sd.fail(path, dest.Type(), v, err)
+		}
+		return nil
+	}
+
+	switch dest.Kind() {
+	case reflect.Interface:
+		if v == nil {
+			dest.Set(reflect.Zero(dest.Type()))
+			return nil
+		}
+		rv := reflect.ValueOf(v)
+		if !rv.Type().AssignableTo(dest.Type()) {
+			return sd.fail(path, dest.Type(), v, errors.New("not assignable"))
+		}
+		dest.Set(rv)
+		return nil
+	case reflect.Ptr:
+		if v == nil {
+			dest.Set(reflect.Zero(dest.Type()))
+			return nil
+		}
+		p := reflect.New(dest.Type().Elem())
+		err := sd.assign(v, p.Elem(), path, tf)
+		if err != nil {
+			return err
+		}
+		dest.Set(p)
+		return nil
+	case reflect.Struct:
+		if cachedStructCodec(dest.Type()).positional {
+			l, ok := asList(v)
+			if !ok {
+				return sd.fail(path, dest.Type(), v, errors.New("expected list"))
+			}
+			return sd.toTuple(l, dest, path, tf)
+		}
+		d, ok := v.(Dictionary)
+		if !ok {
+			return sd.fail(path, dest.Type(), v, errors.New("expected dictionary"))
+		}
+		return sd.toStruct(d, dest, path, tf)
+	case reflect.Map:
+		d, ok := v.(Dictionary)
+		if !ok {
+			return sd.fail(path, dest.Type(), v, errors.New("expected dictionary"))
+		}
+		m := reflect.MakeMapWithSize(dest.Type(), d.Length())
+		for i, k := range d.keys {
+			keyPath := path + pathKey(path, k)
+			key := reflect.New(dest.Type().Key()).Elem()
+			errs := len(sd.errs)
+			err := sd.assign(k, key, keyPath, tf)
+			if err != nil {
+				return err
+			}
+			value := reflect.New(dest.Type().Elem()).Elem()
+			err = sd.assign(d.values[i], value, keyPath, tf)
+			if err != nil {
+				return err
+			}
+			if len(sd.errs) == errs {
+				m.SetMapIndex(key, value)
+			}
+		}
+		dest.Set(m)
+		return nil
+	case reflect.Slice, reflect.Array:
+		l, ok := asList(v)
+		if !ok {
+			return sd.fail(path, dest.Type(), v, errors.New("expected list"))
+		}
+		target := dest
+		if dest.Kind() == reflect.Slice {
+			target = reflect.MakeSlice(dest.Type(), l.Length(), l.Length())
+		} else if l.Length() != dest.Len() {
+			return sd.fail(path, dest.Type(), v, fmt.Errorf("expected %d elements, got %d", dest.Len(), l.Length()))
+		}
+		for i, e := range l.values {
+			err := sd.assign(e, target.Index(i), fmt.Sprintf("%s[%d]", path, i), tf)
+			if err != nil {
+				return err
+			}
+		}
+		dest.Set(target)
+		return nil
+	}
+
+	if t, ok := scalarKinds[dest.Kind()]; ok && t != dest.Type() {
+		// named type: assign to the underlying type and convert
+		tmp := reflect.New(t)
+		err := convertAssign(v, tmp.Interface())
+		if err != nil {
+			return sd.fail(path, dest.Type(), v, err)
+		}
+		dest.Set(tmp.Elem().Convert(dest.Type()))
+		return nil
+	}
+
+	err := convertAssign(v, dest.Addr().Interface())
+	if err != nil {
+		return sd.fail(path, dest.Type(), v, err)
+	}
 	return nil
 }
@@ -19,7 +19,14 @@
 
 package rencode
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+)
 
 func TestToSnakeCase(t *testing.T) {
 	t.Parallel()
@@ -135,3 +142,466 @@ func TestNestedExcludeTag(t *testing.T) {
 		t.Errorf("mapping failed: %v", err)
 	}
 }
+
+func TestDictionaryIndex(t *testing.T) {
+	t.Parallel()
+
+	var d Dictionary
+	d.Add(NewList(1), "unhashable")
+	for i := 0; i < 100; i++ {
+		d.Add([]byte(fmt.Sprintf("key %d", i)), i)
+		d.Add(int8(i), i)
+	}
+	d.Add("key 5", "duplicate")
+
+	for i := 0; i < 100; i++ {
+		v, ok := d.Get(fmt.Sprintf("key %d", i))
+		if !ok || v != i {
+			t.Fatalf("key %d: unexpected value %v", i, v)
+		}
+		v, ok = d.Get([]byte(fmt.Sprintf("key %d", i)))
+		if !ok || v != i {
+			t.Fatalf("key %d: unexpected value %v", i, v)
+		}
+		v, ok = d.Get(int8(i))
+		if !ok || v != i {
+			t.Fatalf("int8 key %d: unexpected value %v", i, v)
+		}
+	}
+	if _, ok := d.Get(int16(5)); ok {
+		t.Error("keys of different integer types must not match")
+	}
+
+	// the index is kept up to date when adding and copies are indexed separately
+	c := d
+	c.Add("new key", true)
+	if v, ok := c.Get("new key"); !ok || v != true {
+		t.Errorf("unexpected value %v for added key", v)
+	}
+	if _, ok := d.Get("new key"); ok {
+		t.Error("key added to copy found in original dictionary")
+	}
+	if v, ok := d.Get("key 99"); !ok || v != 99 {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	// the index is rebuilt when keys are removed or reordered
+	d.Delete("key 0")
+	d.SortKeys(func(a, b interface{}) bool {
+		return FormatText(a) < FormatText(b)
+	})
+	if v, ok := d.Get("key 50"); !ok || v != 50 {
+		t.Errorf("unexpected value %v after deleting and sorting", v)
+	}
+	if d.Has("key 0") {
+		t.Error("deleted key found")
+	}
+}
+
+func TestDictionaryIndexConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var a Dictionary
+	for i := 0; i < 100; i++ {
+		a.Add(int32(i), i)
+	}
+	b := a.Clone()
+	b.Get(int32(50))
+	if !Equal(a, b, EqualOptions{}) {
+		t.Error("lookup modified the dictionary")
+	}
+
+	// copies share the index and lookups can run concurrently, also while building it
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c := a
+				if v, ok := c.Get(int32(j)); !ok || v != j {
+					t.Errorf("unexpected value %v", v)
+				}
+				a.Get(int32(j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// a copy modified after sharing the index uses its own
+	c := a
+	c.Add(int32(100), 100)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			a.Get(int32(j))
+		}
+	}()
+	if v, ok := c.Get(int32(100)); !ok || v != 100 {
+		t.Errorf("unexpected value %v for added key", v)
+	}
+	wg.Wait()
+	if a.Has(int32(100)) {
+		t.Error("key added to copy found in original dictionary")
+	}
+}
+
+func TestDictionaryMutations(t *testing.T) {
+	t.Parallel()
+
+	var d Dictionary
+	d.Add([]byte("a"), 1)
+	d.Add("b", 2)
+	d.Add("a", 3)
+	d.Set("b", 20)
+	d.Set([]byte("c"), 30)
+
+	if !d.Has("a") || !d.Has([]byte("b")) || d.Has("d") {
+		t.Error("unexpected Has result")
+	}
+	if !d.Delete("a") || d.Delete("a") || d.Has("a") {
+		t.Error("expected all occurrences to be deleted")
+	}
+
+	var keys []string
+	var values []interface{}
+	d.Range(func(k, v interface{}) bool {
+		keys = append(keys, FormatText(k))
+		values = append(values, v)
+		return true
+	})
+	if fmt.Sprint(keys, values) != "['b' b'c'] [20 30]" {
+		t.Errorf("unexpected pairs %v %v", keys, values)
+	}
+
+	d.SortKeys(func(a, b interface{}) bool {
+		return FormatText(a) > FormatText(b)
+	})
+	if FormatText(d) != "{b'c': 30, 'b': 20}" {
+		t.Errorf("unexpected sorted dictionary %s", FormatText(d))
+	}
+}
+
+func TestDictionaryClone(t *testing.T) {
+	t.Parallel()
+
+	var nested Dictionary
+	nested.Add("list", NewList([]byte("x")))
+	var d Dictionary
+	d.Add("nested", nested)
+
+	c := d.Clone()
+	v, _ := c.Get("nested")
+	n := v.(Dictionary)
+	n.Set("other", true)
+	v, _ = n.Get("list")
+	l := v.(List)
+	l.Values()[0].([]byte)[0] = 'y'
+
+	if FormatText(d) != "{'nested': {'list': [b'x']}}" {
+		t.Errorf("original dictionary modified: %s", FormatText(d))
+	}
+
+	d.Add("big", bigInt(t, "1180591620717411303424"))
+	c = d.Clone()
+	v, _ = c.Get("big")
+	i := v.(big.Int)
+	i.SetInt64(1)
+	if v, _ := d.Get("big"); FormatText(v) != "1180591620717411303424" {
+		t.Errorf("original big integer modified: %s", FormatText(v))
+	}
+}
+
+func TestDictionaryMerge(t *testing.T) {
+	t.Parallel()
+
+	for _, testCase := range []struct {
+		policy   MergePolicy
+		expected string
+	}{
+		{MergeOverwrite, "{'a': 10, 'b': 2, 'c': 30}"},
+		{MergeKeep, "{'a': 1, 'b': 2, 'c': 30}"},
+		{MergeError, "{'a': 1, 'b': 2}"},
+	} {
+		var d, other Dictionary
+		d.Add("a", 1)
+		d.Add("b", 2)
+		other.Add([]byte("a"), 10)
+		other.Add("c", 30)
+
+		err := d.Merge(&other, testCase.policy)
+		if testCase.policy == MergeError && err != ErrKeyAlreadyExists {
+			t.Errorf("expected ErrKeyAlreadyExists but got %v", err)
+		} else if testCase.policy != MergeError && err != nil {
+			t.Error(err)
+		}
+		if FormatText(d) != testCase.expected {
+			t.Errorf("policy %d: expected %s but got %s", testCase.policy, testCase.expected, FormatText(d))
+		}
+	}
+}
+
+func TestToStructNested(t *testing.T) {
+	t.Parallel()
+
+	v, err := ParseText(`{
+		'name': 'a.iso',
+		'tracker': {'url': 'http://t', 'seeds': 4},
+		'files': {'a/b': {'size': 100}, 'c': {'size': 200}},
+		'priorities': {1: 7},
+		'eta': None,
+		'ratio': 1.5,
+		'pieces': [True, False],
+		'extra': [1, 'x'],
+		'peers': [{'ip': '10.0.0.1', 'port': 6881}],
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := v.(Dictionary)
+
+	type file struct {
+		Size int64
+	}
+	var s struct {
+		Name    string
+		Tracker struct {
+			URL   string
+			Seeds int
+		}
+		Files      map[string]file
+		Priorities map[int]int8
+		Eta        *int64
+		Ratio      *float64
+		Pieces     [2]bool
+		Extra      interface{}
+		Peers      []*struct {
+			IP   string
+			Port int32
+		}
+	}
+	err = d.ToStruct(&s, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Name != "a.iso" || s.Tracker.URL != "http://t" || s.Tracker.Seeds != 4 {
+		t.Errorf("unexpected nested struct %+v", s.Tracker)
+	}
+	if len(s.Files) != 2 || s.Files["a/b"].Size != 100 || s.Files["c"].Size != 200 {
+		t.Errorf("unexpected map %+v", s.Files)
+	}
+	if s.Priorities[1] != 7 {
+		t.Errorf("unexpected map %+v", s.Priorities)
+	}
+	if s.Eta != nil || s.Ratio == nil || *s.Ratio != 1.5 {
+		t.Errorf("unexpected pointers %v %v", s.Eta, s.Ratio)
+	}
+	if s.Pieces != [2]bool{true, false} {
+		t.Errorf("unexpected array %v", s.Pieces)
+	}
+	if FormatText(s.Extra) != "[1, b'x']" {
+		t.Errorf("unexpected interface value %s", FormatText(s.Extra))
+	}
+	if len(s.Peers) != 1 || s.Peers[0].IP != "10.0.0.1" || s.Peers[0].Port != 6881 {
+		t.Errorf("unexpected peers %+v", s.Peers)
+	}
+
+	var wrongArray struct {
+		Pieces [3]bool
+	}
+	var pieces Dictionary
+	pieces.Add("pieces", NewList(true, false))
+	if err := pieces.ToStruct(&wrongArray, ""); err == nil {
+		t.Error("expected failure for array length mismatch")
+	}
+}
+
+func TestToStructWithOptions(t *testing.T) {
+	t.Parallel()
+
+	v, err := ParseText(`{'name': 'a.iso', 'ratio': 1.5, 'new_key': 1, 'other': b'x'}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := v.(Dictionary)
+
+	type status struct {
+		Name     string
+		Ratio    float64
+		Label    string `rencode:"optional"`
+		MaxPeers int    `rencode:"default=50"`
+		Trackers List   `rencode:"default=['a', 'b']"`
+	}
+
+	var strict status
+	if err := d.ToStruct(&strict, ""); err == nil {
+		t.Error("expected failure in strict mode")
+	}
+
+	var s status
+	s.Label = "keep"
+	err = d.ToStructWithOptions(&s, StructOptions{IgnoreUnknownKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "a.iso" || s.Ratio != 1.5 || s.Label != "keep" || s.MaxPeers != 50 {
+		t.Errorf("unexpected struct %+v", s)
+	}
+	if FormatText(s.Trackers) != "[b'a', b'b']" {
+		t.Errorf("unexpected default list %s", FormatText(s.Trackers))
+	}
+
+	var withRemain struct {
+		Name  string
+		Rest  map[string]interface{} `rencode:",remain"`
+		Ratio float64
+	}
+	err = d.ToStruct(&withRemain, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withRemain.Rest) != 2 || withRemain.Rest["new_key"] != int8(1) {
+		t.Errorf("unexpected remaining keys %v", withRemain.Rest)
+	}
+
+	var missing struct {
+		Name  string
+		Ratio float64
+		Eta   int64
+		Other []byte
+		Extra Dictionary `rencode:"remain"`
+	}
+	if err := d.ToStruct(&missing, ""); err == nil {
+		t.Error("expected failure for missing field")
+	}
+	err = d.ToStructWithOptions(&missing, StructOptions{AllowMissing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing.Eta != 0 || string(missing.Other) != "x" || FormatText(missing.Extra) != "{b'new_key': 1}" {
+		t.Errorf("unexpected struct %+v", missing)
+	}
+
+	var badDefault struct {
+		Name string `rencode:"default=[1"`
+	}
+	var empty Dictionary
+	if err := empty.ToStruct(&badDefault, ""); err == nil {
+		t.Error("expected failure for invalid default")
+	}
+}
+
+func TestToStructAllErrors(t *testing.T) {
+	t.Parallel()
+
+	v, err := ParseText(`{
+		'name': 1,
+		'tracker': {'url': 'http://t', 'seeds': 'many'},
+		'peers': [{'port': 6881}, {'port': 'x'}],
+		'files': {'a': 'b'},
+		'unknown': None,
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := v.(Dictionary)
+
+	var s struct {
+		Name    string
+		Ratio   float64
+		Tracker struct {
+			URL   string
+			Seeds int
+		}
+		Peers []struct {
+			Port int32
+		}
+		Files map[string]int
+	}
+
+	err = d.ToStruct(&s, "")
+	fe, ok := err.(*FieldError)
+	if !ok {
+		t.Fatalf("expected *FieldError but got %T: %v", err, err)
+	}
+	if fe.Path != "name" || fe.Type.Kind() != reflect.String || fe.Value != int8(1) {
+		t.Errorf("unexpected first error %+v", fe)
+	}
+
+	err = d.ToStructWithOptions(&s, StructOptions{AllErrors: true})
+	errs, ok := err.(StructErrors)
+	if !ok {
+		t.Fatalf("expected StructErrors but got %T: %v", err, err)
+	}
+	var paths []string
+	for _, err := range errs {
+		paths = append(paths, err.(*FieldError).Path)
+	}
+	expected := []string{"name", "ratio", "tracker.seeds", "peers[1].port", "files.a", "unknown"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected errors at %v but got %v", expected, paths)
+	}
+	if !errors.Is(err, ErrMissingField) || !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected missing field and unknown key errors in %v", err)
+	}
+	if errs[1].Error() != "ratio: expected float64, got no value" {
+		t.Errorf("unexpected message %q", errs[1].Error())
+	}
+	if errs[5].Error() != "unknown: no field for key" {
+		t.Errorf("unexpected message %q", errs[5].Error())
+	}
+
+	// all valid fields are still assigned
+	if s.Tracker.URL != "http://t" || len(s.Peers) != 2 || s.Peers[0].Port != 6881 {
+		t.Errorf("unexpected struct %+v", s)
+	}
+}
+
+func TestDictionaryContainerAndBigKeys(t *testing.T) {
+	t.Parallel()
+
+	var big1 big.Int
+	big1.SetString("18446744073709551616", 10)
+	var big2 big.Int
+	big2.SetString("18446744073709551616", 10)
+
+	var d Dictionary
+	d.Add(big1, 1)
+	d.Add(NewList(int8(1), []byte("a")), 2)
+	d.Add(NewTuple(int8(1), int8(2)), 3)
+	d.Add("a", 4)
+
+	if !d.Has(big2) || !d.Has(NewList(int8(1), "a")) || !d.Has(NewTuple(int8(1), int8(2))) {
+		t.Error("expected big integer, list and tuple keys to be found")
+	}
+	if d.Has(NewTuple(int8(1), int8(3))) || d.Has(NewList(int8(1), int8(2))) {
+		t.Error("unexpected match for different container key")
+	}
+
+	d.Set(NewTuple(int8(1), int8(2)), 30)
+	if v, ok := d.Get(NewTuple(int8(1), int8(2))); !ok || v != 30 {
+		t.Errorf("unexpected value %v for tuple key", v)
+	}
+
+	var other Dictionary
+	other.Add(big2, 10)
+	other.Add(NewList(int8(1), "a"), 20)
+	if err := d.Merge(&other, MergeError); err != ErrKeyAlreadyExists {
+		t.Errorf("expected ErrKeyAlreadyExists but got %v", err)
+	}
+	if err := d.Merge(&other, MergeOverwrite); err != nil {
+		t.Fatal(err)
+	}
+	if d.Length() != 4 {
+		t.Errorf("expected merged keys to replace existing ones, got %s", FormatText(d))
+	}
+
+	if !d.Delete(big2) || !d.Delete(NewList(int8(1), "a")) || d.Has(big1) {
+		t.Error("expected big integer and list keys to be deleted")
+	}
+	if FormatText(d) != "{(1, 2): 30, 'a': 4}" {
+		t.Errorf("unexpected dictionary %s", FormatText(d))
+	}
+}
@@ -25,6 +25,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
 )
 
 // Constants as defined in https://github.com/aresch/rencode/blob/master/rencode/rencode.pyx
@@ -63,12 +65,13 @@ const (
 
 // Encoder implements a rencode encoder
 type Encoder struct {
-	w io.Writer
+	w          io.Writer
+	timeFormat TimeFormat
 }
 
 // NewEncoder returns a rencode encoder that writes on specified Writer
 func NewEncoder(w io.Writer) Encoder {
-	return Encoder{w}
+	return Encoder{w: w}
 }
 
 // EncodeInt8 encodes an int8 value
@@ -194,6 +197,8 @@ func (r *Encoder) EncodeFloat64(f float64) error {
 //  - []byte, string (all strings are stored as byte slices anyway)
 //  - int8, int16, int32, int64, int
 //  - uint8, uint16, uint32, uint64, uint
+// Marshaler values encode themselves, time.Time and time.Duration values are represented as set
+// with SetTimeFormat and other values, like structs and maps, are converted as by FromStruct.
 func (r *Encoder) Encode(values ...interface{}) error {
 	for _, v := range values {
 		err := r.encodeSingle(v)
@@ -204,3 +209,43 @@ func (r *Encoder) Encode(values ...interface{}) error {
 
 	return nil
 }
+
+// BeginList writes the type code starting a list of n elements; if the returned bool is true
+// the list has no embedded length and must be terminated with EndContainer.
+// A terminated list can be forced by specifying a negative n.
+func (r *Encoder) BeginList(n int) (bool, error) {
+	if 0 <= n && n < LIST_FIXED_COUNT {
+		_, err := r.w.Write([]byte{byte(LIST_FIXED_START + n)})
+		return false, err
+	}
+	_, err := r.w.Write([]byte{CHR_LIST})
+	return true, err
+}
+
+// BeginDict writes the type code starting a dictionary of n key/value pairs; if the returned bool
+// is true the dictionary has no embedded length and must be terminated with EndContainer.
+// A terminated dictionary can be forced by specifying a negative n.
+func (r *Encoder) BeginDict(n int) (bool, error) {
+	if 0 <= n && n < DICT_FIXED_COUNT {
+		_, err := r.w.Write([]byte{byte(DICT_FIXED_START + n)})
+		return false, err
+	}
+	_, err := r.w.Write([]byte{CHR_DICT})
+	return true, err
+}
+
+// EndContainer terminates a list or dictionary
+func (r *Encoder) EndContainer() error {
+	_, err := r.w.Write([]byte{CHR_TERM})
+	return err
+}
+
+// encodeUnsigned encodes an unsigned integer with the narrowest signed type, or as big number if needed
+func (r *Encoder) encodeUnsigned(v uint64) error {
+	if v > math.MaxInt64 {
+		var i big.Int
+		i.SetUint64(v)
+		return r.EncodeBigNumber(i.String())
+	}
+	return r.encodeSingle(int64(v))
+}
@@ -0,0 +1,326 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EqualOptions relax the comparison performed by Equal and Diff
+type EqualOptions struct {
+	// IgnoreNumericWidth compares integers of any type by value, and float32 with float64 values
+	IgnoreNumericWidth bool
+	// IgnoreBytesString compares string and []byte values by content
+	IgnoreBytesString bool
+}
+
+// ChangeKind identifies the kind of a Change
+type ChangeKind int
+
+const (
+	// Added is a dictionary key or list element which exists only in the new value
+	Added ChangeKind = iota
+	// Removed is a dictionary key or list element which exists only in the old value
+	Removed
+	// Modified is a value which differs in type or content
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Change is a difference between two values, as returned by Diff
+type Change struct {
+	Kind ChangeKind
+	// Path addresses the changed value with the syntax of ParsePath
+	Path string
+	// Old is the value being removed or modified, nil for Added
+	Old interface{}
+	// New is the value being added or the modified value, nil for Removed
+	New interface{}
+	// Key is the dictionary key of an added or removed dictionary entry, with its type
+	Key interface{}
+}
+
+func (c Change) String() string {
+	path := c.Path
+	if path == "" {
+		path = "(root)"
+	}
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: %s", c.Kind, path, FormatText(c.New))
+	case Removed:
+		return fmt.Sprintf("%s %s: %s", c.Kind, path, FormatText(c.Old))
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", c.Kind, path, FormatText(c.Old), FormatText(c.New))
+}
+
+// Equal returns true if the two values are deeply equal. Dictionaries are compared regardless of
+// the order of their keys, which are matched like Dictionary.Get; lists and tuples are compared element
+// by element and sets regardless of the order of their elements, which are matched like Set.Has.
+func Equal(a, b interface{}, opts EqualOptions) bool {
+	d := differ{opts: opts, stopAtFirst: true}
+	d.diff("", a, b)
+	return len(d.changes) == 0
+}
+
+// Diff returns the changes which turn the value a into the value b, see Equal;
+// the changes can be applied in order.
+func Diff(a, b interface{}) []Change {
+	return EqualOptions{}.Diff(a, b)
+}
+
+// Diff returns the changes which turn the value a into the value b, comparing values with the options.
+func (opts EqualOptions) Diff(a, b interface{}) []Change {
+	d := differ{opts: opts}
+	d.diff("", a, b)
+	return d.changes
+}
+
+type differ struct {
+	opts        EqualOptions
+	stopAtFirst bool
+	changes     []Change
+}
+
+func (d *differ) add(c Change) {
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) done() bool {
+	return d.stopAtFirst && len(d.changes) != 0
+}
+
+func (d *differ) diff(path string, a, b interface{}) {
+	switch x := a.(type) {
+	case List:
+		if y, ok := b.(List); ok {
+			d.diffLists(path, x, y)
+			return
+		}
+	case Tuple:
+		if y, ok := b.(Tuple); ok {
+			d.diffLists(path, x.List, y.List)
+			return
+		}
+	case Set:
+		// sets are compared as a whole, since their elements have no path
+		if y, ok := b.(Set); ok && setsEqual(&x, &y) {
+			return
+		}
+	case Dictionary:
+		if y, ok := b.(Dictionary); ok {
+			d.diffDicts(path, x, y)
+			return
+		}
+	}
+
+	if !d.scalarsEqual(a, b) {
+		d.add(Change{Kind: Modified, Path: path, Old: a, New: b})
+	}
+}
+
+func (d *differ) diffLists(path string, a, b List) {
+	for i := 0; i < len(b.values); i++ {
+		if d.done() {
+			return
+		}
+		p := path + "[" + strconv.Itoa(i) + "]"
+		if i >= len(a.values) {
+			d.add(Change{Kind: Added, Path: p, New: b.values[i]})
+			continue
+		}
+		d.diff(p, a.values[i], b.values[i])
+	}
+
+	// removed elements are reported from the last one, so that changes can be applied in order
+	for i := len(a.values) - 1; i >= len(b.values) && !d.done(); i-- {
+		d.add(Change{Kind: Removed, Path: path + "[" + strconv.Itoa(i) + "]", Old: a.values[i]})
+	}
+}
+
+func (d *differ) diffDicts(path string, a, b Dictionary) {
+	matched := make([]bool, len(b.keys))
+	for i, k := range a.keys {
+		if d.done() {
+			return
+		}
+		p := path + pathKey(path, k)
+		j := d.findKey(&b, k)
+		if j < 0 {
+			d.add(Change{Kind: Removed, Path: p, Old: a.values[i], Key: k})
+			continue
+		}
+		matched[j] = true
+		d.diff(p, a.values[i], b.values[j])
+	}
+
+	for j, k := range b.keys {
+		if d.done() {
+			return
+		}
+		if !matched[j] && d.findKey(&a, k) < 0 {
+			d.add(Change{Kind: Added, Path: path + pathKey(path, k), New: b.values[j], Key: k})
+		}
+	}
+}
+
+// findKey returns the position of the key in the dictionary, matching keys like Get
+// and integers regardless of their width if IgnoreNumericWidth is set; keys like lists
+// and tuples are compared like values.
+func (d *differ) findKey(dict *Dictionary, key interface{}) int {
+	if _, ok := indexKey(key); ok {
+		if i := dict.find(key); i >= 0 || !d.opts.IgnoreNumericWidth {
+			return i
+		}
+	}
+	for i, k := range dict.keys {
+		if d.keysEqual(normalizeKey(k), normalizeKey(key)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// keysEqual returns true if the normalized keys are equal with the options of the differ
+func (d *differ) keysEqual(a, b interface{}) bool {
+	keys := differ{opts: d.opts, stopAtFirst: true}
+	keys.diff("", a, b)
+	return len(keys.changes) == 0
+}
+
+// pathKey returns the path step addressing a dictionary key
+func pathKey(path string, key interface{}) string {
+	var name string
+	switch k := key.(type) {
+	case []byte:
+		name = string(k)
+	case string:
+		name = k
+	default:
+		return "[" + FormatText(key) + "]"
+	}
+
+	if name == "" || name == "*" || strings.ContainsAny(name, ".[]'\"\\") {
+		return "[" + FormatText(key) + "]"
+	}
+	if path == "" {
+		return name
+	}
+	return "." + name
+}
+
+// setsEqual returns true if the sets have the same elements, regardless of their order
+func setsEqual(a, b *Set) bool {
+	if a.Length() != b.Length() {
+		return false
+	}
+	for _, v := range a.values {
+		if !b.Has(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// bigValue returns the value of an integer of any type
+func bigValue(v interface{}) (*big.Int, bool) {
+	var i big.Int
+	switch x := v.(type) {
+	case int8, int16, int32, int64, int:
+		n, _ := pathInteger(x)
+		return i.SetInt64(n), true
+	case uint8:
+		return i.SetUint64(uint64(x)), true
+	case uint16:
+		return i.SetUint64(uint64(x)), true
+	case uint32:
+		return i.SetUint64(uint64(x)), true
+	case uint64:
+		return i.SetUint64(x), true
+	case uint:
+		return i.SetUint64(uint64(x)), true
+	case big.Int:
+		return &x, true
+	}
+	return nil, false
+}
+
+func (d *differ) scalarsEqual(a, b interface{}) bool {
+	if d.opts.IgnoreBytesString {
+		if s, ok := a.(string); ok {
+			a = []byte(s)
+		}
+		if s, ok := b.(string); ok {
+			b = []byte(s)
+		}
+	}
+	if d.opts.IgnoreNumericWidth {
+		if x, ok := bigValue(a); ok {
+			y, ok := bigValue(b)
+			return ok && x.Cmp(y) == 0
+		}
+		if x, ok := a.(float32); ok {
+			a = float64(x)
+		}
+		if y, ok := b.(float32); ok {
+			b = float64(y)
+		}
+	}
+
+	switch x := a.(type) {
+	case []byte:
+		y, ok := b.([]byte)
+		return ok && bytes.Equal(x, y)
+	case big.Int:
+		y, ok := b.(big.Int)
+		return ok && x.Cmp(&y) == 0
+	case float32:
+		y, ok := b.(float32)
+		return ok && (x == y || (math.IsNaN(float64(x)) && math.IsNaN(float64(y))))
+	case float64:
+		y, ok := b.(float64)
+		return ok && (x == y || (math.IsNaN(x) && math.IsNaN(y)))
+	case List, Tuple, Set, Dictionary:
+		return false
+	}
+
+	// values of other types which are not comparable are never equal
+	if a != nil && !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
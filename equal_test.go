@@ -0,0 +1,122 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	a, err := ParseText(`{'a': [1, 2.5, b'x', None], 'b': {1: True}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inner Dictionary
+	inner.Add(int64(1), true)
+	var b Dictionary
+	b.Add("b", inner)
+	b.Add("a", NewList(int32(1), float32(2.5), "x", nil))
+
+	for _, testCase := range []struct {
+		opts     EqualOptions
+		expected bool
+	}{
+		{EqualOptions{}, false},
+		{EqualOptions{IgnoreNumericWidth: true}, false},
+		{EqualOptions{IgnoreBytesString: true}, false},
+		{EqualOptions{IgnoreNumericWidth: true, IgnoreBytesString: true}, true},
+	} {
+		if Equal(a, b, testCase.opts) != testCase.expected {
+			t.Errorf("%+v: expected %v", testCase.opts, testCase.expected)
+		}
+	}
+
+	if !Equal(a, a, EqualOptions{}) {
+		t.Error("value not equal to itself")
+	}
+}
+
+func TestEqualContainerKeys(t *testing.T) {
+	t.Parallel()
+
+	a, err := ParseText(`{(1, 2): 'x', 3: 'y', [b'a', (4,)]: 'z'}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(a, a, EqualOptions{}) {
+		t.Error("dictionary with container keys not equal to itself")
+	}
+
+	var b Dictionary
+	b.Add(NewList("a", NewList(int64(4))), []byte("z"))
+	b.Add(int8(3), []byte("y"))
+	b.Add(NewList(int32(1), int16(2)), []byte("x"))
+	if Equal(a, b, EqualOptions{}) {
+		t.Error("expected keys of different width and bytes to differ")
+	}
+	if !Equal(a, b, EqualOptions{IgnoreNumericWidth: true, IgnoreBytesString: true}) {
+		t.Errorf("expected equal dictionaries, got %v", Diff(a, b))
+	}
+
+	c, err := ParseText(`{(1, 3): 'x', 3: 'y', [b'a', (4,)]: 'z'}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Equal(a, c, EqualOptions{}) {
+		t.Error("expected different tuple keys to differ")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	a, err := ParseText(`{'torrents': {'abc': {'name': 'a', 'peers': [1, 2, 3]}, 'x.y': 1}, 'gone': None}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseText(`{'torrents': {'abc': {'name': 'b', 'peers': [1, 2]}, 'x.y': 1.0}, 'new': [], 3: 4}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"modified torrents.abc.name: b'a' -> b'b'",
+		"removed torrents.abc.peers[2]: 3",
+		"modified torrents[b'x.y']: 1 -> 1.0",
+		"removed gone: None",
+		"added new: []",
+		"added [3]: 4",
+	}
+	changes := Diff(a, b)
+	if fmt.Sprint(changes) != fmt.Sprint(expected) {
+		t.Errorf("unexpected changes:\n%v", changes)
+	}
+
+	for _, c := range changes[:3] {
+		matches, err := Lookup(a, c.Path)
+		if err != nil || len(matches) != 1 || !Equal(matches[0], c.Old, EqualOptions{}) {
+			t.Errorf("path %q does not address %v: %v (%v)", c.Path, c.Old, matches, err)
+		}
+	}
+}
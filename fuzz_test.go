@@ -0,0 +1,214 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+//go:build go1.18
+// +build go1.18
+
+package rencode
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+)
+
+// fuzzSeeds returns the encodings of the values used by the tests in rencode_test.go
+func fuzzSeeds(f *testing.F) [][]byte {
+	var bigNumber big.Int
+	bigNumber.SetString("18446744073709551616", 10)
+
+	var nestedDict Dictionary
+	nestedDict.Add("abcdefghijk", int16(1234))
+	nestedDict.Add(false, []byte("bäz"))
+	nestedList := NewList(true, "carrot")
+	var dict Dictionary
+	for i := 0; i < 30; i++ {
+		dict.Add([]byte{'k', byte(i)}, nestedDict)
+		dict.Add([]byte{'z', byte(i)}, nestedList)
+	}
+
+	values := []interface{}{
+		int8(10), int8(-10), int8(100), int8(-100), int16(27123), int16(-27123),
+		int32(7483648), int32(-7483648), int64(8223372036854775808), int64(-8223372036854775808),
+		bigNumber, maxUint64, float32(1234.56), 1234.56, "foobarbaz", "fööbar",
+		[]byte{0x02}, []byte(string(make([]byte, 100))), nil, true, false,
+		nestedDict, nestedList, dict,
+	}
+
+	var seeds [][]byte
+	for _, v := range values {
+		var b bytes.Buffer
+		e := NewEncoder(&b)
+		err := e.Encode(v)
+		if err != nil {
+			f.Fatal(err)
+		}
+		seeds = append(seeds, b.Bytes())
+	}
+
+	// malformed inputs
+	seeds = append(seeds,
+		[]byte{CHR_INT, '1', '2'},
+		[]byte{CHR_LIST, CHR_LIST},
+		[]byte("9999999999999999:x"),
+		[]byte{DICT_FIXED_START + 1, CHR_TRUE},
+		[]byte{LIST_FIXED_START + 2, CHR_NONE},
+	)
+	return seeds
+}
+
+func FuzzDecodeNext(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		d := NewDecoder(bytes.NewReader(data))
+		for {
+			start := d.Offset()
+			v, err := d.DecodeNext()
+			if err == io.EOF && start != int64(len(data)) {
+				t.Fatalf("truncated value at offset %d reported as end of stream", start)
+			}
+			if err != nil {
+				return
+			}
+
+			// decode(encode(v)) must be equivalent to v and encoding must be stable
+			var b bytes.Buffer
+			e := NewEncoder(&b)
+			err = e.Encode(v)
+			if err != nil {
+				t.Fatalf("could not encode decoded value %s: %v", FormatText(v), err)
+			}
+			encoded := append([]byte(nil), b.Bytes()...)
+
+			v2, err := NewDecoder(&b).DecodeNext()
+			if err != nil {
+				t.Fatalf("could not decode % x: %v", encoded, err)
+			}
+			if FormatText(v) != FormatText(v2) {
+				t.Fatalf("round trip mismatch: %s != %s", FormatText(v), FormatText(v2))
+			}
+
+			e = NewEncoder(&b)
+			err = e.Encode(v2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(encoded, b.Bytes()) {
+				t.Fatalf("unstable encoding: % x != % x", encoded, b.Bytes())
+			}
+		}
+	})
+}
+
+func FuzzScan(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var (
+			i8  int8
+			u64 uint64
+			f32 float32
+			s   string
+			b   bool
+			l   List
+			d   Dictionary
+		)
+		for _, target := range []interface{}{&i8, &u64, &f32, &s, &b, &l, &d} {
+			_ = NewDecoder(bytes.NewReader(data)).Scan(target)
+		}
+	})
+}
+
+func FuzzToStruct(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+
+	type inner struct {
+		Abcdefghijk int16
+		Name        string
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := NewDecoder(bytes.NewReader(data)).DecodeNext()
+		if err != nil {
+			return
+		}
+		d, ok := v.(Dictionary)
+		if !ok {
+			return
+		}
+
+		var dest struct {
+			Abcdefghijk int16
+			Ratio       float64
+			Name        string
+			Flags       []bool
+			Files       []inner
+			Priorities  []int32
+		}
+		_ = d.ToStruct(&dest, "")
+	})
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	f.Add(int64(0), 0.5, float32(1.5), []byte("foo"), true)
+	f.Add(int64(-8223372036854775808), 1234.56, float32(-1), []byte{}, false)
+
+	f.Fuzz(func(t *testing.T, i int64, f64 float64, f32 float32, s []byte, b bool) {
+		var dict Dictionary
+		dict.Add(s, NewList(i, f64, f32, b, nil))
+		dict.Add(i, s)
+
+		var buf bytes.Buffer
+		e := NewEncoder(&buf)
+		err := e.Encode(dict, NewList(s, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := NewDecoder(&buf)
+		var (
+			decoded Dictionary
+			l       List
+		)
+		err = d.Scan(&decoded, &l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if FormatText(dict) != FormatText(decoded) {
+			t.Fatalf("round trip mismatch: %s != %s", FormatText(dict), FormatText(decoded))
+		}
+
+		var s2 []byte
+		var i2 int64
+		err = l.Scan(&s2, &i2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(s, s2) || i != i2 {
+			t.Fatalf("round trip mismatch: %q, %d != %q, %d", s, i, s2, i2)
+		}
+	})
+}
@@ -209,23 +209,31 @@ func unsignedGenerate(t string, bitsize int) {
 			return r.EncodeInt8(int8(x))
 		}`)
 
-	if bitsize >= 16 {
-		fmt.Println(`		if x <= math.MaxInt16 {
+	// values not fitting a signed type of the same size use the next larger one
+	if bitsize == 8 {
+		fmt.Println(`		return r.EncodeInt16(int16(x))`)
+		return
+	}
+
+	fmt.Println(`		if x <= math.MaxInt16 {
 		return r.EncodeInt16(int16(x))
 		}`)
+
+	if bitsize == 16 {
+		fmt.Println(`		return r.EncodeInt32(int32(x))`)
+		return
 	}
 
-	if bitsize >= 32 {
-		fmt.Println(`		if x <= math.MaxInt32 {
+	fmt.Println(`		if x <= math.MaxInt32 {
 		return r.EncodeInt32(int32(x))
 		}`)
-		return
-	}
 
-	if bitsize == 63 {
+	if bitsize == 32 {
 		fmt.Println(`		return r.EncodeInt64(int64(x))`)
 		return
 	}
+
+	panic("unsigned: using bitsize larger than 32")
 }
 
 func main() {
@@ -257,9 +265,8 @@ func main() {
 
 	// tail default case
 	fmt.Println(`	default:
-		return fmt.Errorf("could not encode data of type %T", data)
+		return r.encodeReflected(data)
 	}
-	panic("unexpected fallthrough")
 }`)
 
 	// generate integer conversion function
@@ -0,0 +1,74 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+// Package gentest contains types marshaled with code generated by rencodegen, to test it.
+package gentest
+
+//go:generate go run ../../cmd/rencodegen -type Status,Tracker,Peer -exclude private -o types_rencode.go
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// State is the state of a torrent
+type State string
+
+// Priorities are the priorities of the files of a torrent
+type Priorities []int8
+
+// Status is the status of a torrent
+type Status struct {
+	Name        string
+	State       State
+	Progress    float64
+	TotalSize   int64
+	NumSeeds    int
+	IsFinished  bool
+	Hash        []byte
+	Label       string  `rencode:"optional"`
+	MaxDownload float64 `rencode:"default=-1.0"`
+	Trackers    []Tracker
+	Peers       map[string]*Peer
+	Priorities  Priorities
+	Pieces      [2]bool
+	Eta         *int64
+	Extra       interface{}
+	Options     rencode.Dictionary
+	Big         big.Int
+	Added       time.Time
+	Interval    *time.Duration
+	Secret      string `rencode:"private"`
+	internal    int
+}
+
+// Tracker is a tracker of a torrent
+type Tracker struct {
+	URL  string
+	Tier int8
+}
+
+// Peer is a peer connected for a torrent
+type Peer struct {
+	IP     string
+	Port   int32
+	Client *string
+}
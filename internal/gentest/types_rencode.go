@@ -0,0 +1,666 @@
+// Code generated by rencodegen; DO NOT EDIT.
+
+package gentest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// MarshalRencode encodes Status as a dictionary, like FromStruct
+func (x Status) MarshalRencode(e *rencode.Encoder) error {
+	term, err := e.BeginDict(19)
+	if err != nil {
+		return err
+	}
+	if err := e.Encode("name"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Name); err != nil {
+		return err
+	}
+	if err := e.Encode("state"); err != nil {
+		return err
+	}
+	if err := e.Encode(string(x.State)); err != nil {
+		return err
+	}
+	if err := e.Encode("progress"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Progress); err != nil {
+		return err
+	}
+	if err := e.Encode("total_size"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.TotalSize); err != nil {
+		return err
+	}
+	if err := e.Encode("num_seeds"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.NumSeeds); err != nil {
+		return err
+	}
+	if err := e.Encode("is_finished"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.IsFinished); err != nil {
+		return err
+	}
+	if err := e.Encode("hash"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Hash); err != nil {
+		return err
+	}
+	if err := e.Encode("label"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Label); err != nil {
+		return err
+	}
+	if err := e.Encode("max_download"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.MaxDownload); err != nil {
+		return err
+	}
+	if err := e.Encode("trackers"); err != nil {
+		return err
+	}
+	term1, err := e.BeginList(len(x.Trackers))
+	if err != nil {
+		return err
+	}
+	for _, v2 := range x.Trackers {
+		if err := v2.MarshalRencode(e); err != nil {
+			return err
+		}
+	}
+	if term1 {
+		if err := e.EndContainer(); err != nil {
+			return err
+		}
+	}
+	if err := e.Encode("peers"); err != nil {
+		return err
+	}
+	term3, err := e.BeginDict(len(x.Peers))
+	if err != nil {
+		return err
+	}
+	keys4 := make([]string, 0, len(x.Peers))
+	for k5 := range x.Peers {
+		keys4 = append(keys4, k5)
+	}
+	sort.Slice(keys4, func(i, j int) bool {
+		return keys4[i] < keys4[j]
+	})
+	for _, k5 := range keys4 {
+		if err := e.Encode(k5); err != nil {
+			return err
+		}
+		if x.Peers[k5] == nil {
+			if err := e.EncodeNone(); err != nil {
+				return err
+			}
+		} else {
+			if err := (*x.Peers[k5]).MarshalRencode(e); err != nil {
+				return err
+			}
+		}
+	}
+	if term3 {
+		if err := e.EndContainer(); err != nil {
+			return err
+		}
+	}
+	if err := e.Encode("priorities"); err != nil {
+		return err
+	}
+	term6, err := e.BeginList(len(x.Priorities))
+	if err != nil {
+		return err
+	}
+	for _, v7 := range x.Priorities {
+		if err := e.Encode(v7); err != nil {
+			return err
+		}
+	}
+	if term6 {
+		if err := e.EndContainer(); err != nil {
+			return err
+		}
+	}
+	if err := e.Encode("pieces"); err != nil {
+		return err
+	}
+	term8, err := e.BeginList(len(x.Pieces))
+	if err != nil {
+		return err
+	}
+	for _, v9 := range x.Pieces {
+		if err := e.Encode(v9); err != nil {
+			return err
+		}
+	}
+	if term8 {
+		if err := e.EndContainer(); err != nil {
+			return err
+		}
+	}
+	if err := e.Encode("eta"); err != nil {
+		return err
+	}
+	if x.Eta == nil {
+		if err := e.EncodeNone(); err != nil {
+			return err
+		}
+	} else {
+		if err := e.Encode((*x.Eta)); err != nil {
+			return err
+		}
+	}
+	if err := e.Encode("extra"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Extra); err != nil {
+		return err
+	}
+	if err := e.Encode("options"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Options); err != nil {
+		return err
+	}
+	if err := e.Encode("big"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Big); err != nil {
+		return err
+	}
+	if err := e.Encode("added"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Added); err != nil {
+		return err
+	}
+	if err := e.Encode("interval"); err != nil {
+		return err
+	}
+	if x.Interval == nil {
+		if err := e.EncodeNone(); err != nil {
+			return err
+		}
+	} else {
+		if err := e.Encode((*x.Interval)); err != nil {
+			return err
+		}
+	}
+	if term {
+		return e.EndContainer()
+	}
+	return nil
+}
+
+// UnmarshalRencode decodes Status from a dictionary, like ToStruct
+func (x *Status) UnmarshalRencode(d *rencode.Decoder) error {
+	var seen [19]bool
+	err := d.DecodeDict(func() error {
+		var key []byte
+		if err := d.Scan(&key); err != nil {
+			return err
+		}
+		switch string(key) {
+		case "name":
+			if seen[0] {
+				return &rencode.FieldError{Path: "name", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[0] = true
+			if err := d.Scan(&x.Name); err != nil {
+				return err
+			}
+		case "state":
+			if seen[1] {
+				return &rencode.FieldError{Path: "state", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[1] = true
+			var v1 string
+			if err := d.Scan(&v1); err != nil {
+				return err
+			}
+			x.State = State(v1)
+		case "progress":
+			if seen[2] {
+				return &rencode.FieldError{Path: "progress", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[2] = true
+			if err := d.Scan(&x.Progress); err != nil {
+				return err
+			}
+		case "total_size":
+			if seen[3] {
+				return &rencode.FieldError{Path: "total_size", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[3] = true
+			if err := d.Scan(&x.TotalSize); err != nil {
+				return err
+			}
+		case "num_seeds":
+			if seen[4] {
+				return &rencode.FieldError{Path: "num_seeds", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[4] = true
+			if err := d.Scan(&x.NumSeeds); err != nil {
+				return err
+			}
+		case "is_finished":
+			if seen[5] {
+				return &rencode.FieldError{Path: "is_finished", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[5] = true
+			if err := d.Scan(&x.IsFinished); err != nil {
+				return err
+			}
+		case "hash":
+			if seen[6] {
+				return &rencode.FieldError{Path: "hash", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[6] = true
+			if err := d.Scan(&x.Hash); err != nil {
+				return err
+			}
+		case "label":
+			if seen[7] {
+				return &rencode.FieldError{Path: "label", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[7] = true
+			if err := d.Scan(&x.Label); err != nil {
+				return err
+			}
+		case "max_download":
+			if seen[8] {
+				return &rencode.FieldError{Path: "max_download", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[8] = true
+			if err := d.Scan(&x.MaxDownload); err != nil {
+				return err
+			}
+		case "trackers":
+			if seen[9] {
+				return &rencode.FieldError{Path: "trackers", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[9] = true
+			x.Trackers = make([]Tracker, 0)
+			if err := d.DecodeList(func() error {
+				var v2 Tracker
+				if err := v2.UnmarshalRencode(d); err != nil {
+					return err
+				}
+				x.Trackers = append(x.Trackers, v2)
+				return nil
+			}); err != nil {
+				return err
+			}
+		case "peers":
+			if seen[10] {
+				return &rencode.FieldError{Path: "peers", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[10] = true
+			x.Peers = make(map[string]*Peer)
+			if err := d.DecodeDict(func() error {
+				var k3 string
+				if err := d.Scan(&k3); err != nil {
+					return err
+				}
+				var v4 *Peer
+				if none, err := d.DecodeNone(); err != nil {
+					return err
+				} else if none {
+					v4 = nil
+				} else {
+					v4 = new(Peer)
+					if err := (*v4).UnmarshalRencode(d); err != nil {
+						return err
+					}
+				}
+				x.Peers[k3] = v4
+				return nil
+			}); err != nil {
+				return err
+			}
+		case "priorities":
+			if seen[11] {
+				return &rencode.FieldError{Path: "priorities", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[11] = true
+			x.Priorities = make(Priorities, 0)
+			if err := d.DecodeList(func() error {
+				var v5 int8
+				if err := d.Scan(&v5); err != nil {
+					return err
+				}
+				x.Priorities = append(x.Priorities, v5)
+				return nil
+			}); err != nil {
+				return err
+			}
+		case "pieces":
+			if seen[12] {
+				return &rencode.FieldError{Path: "pieces", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[12] = true
+			i6 := 0
+			if err := d.DecodeList(func() error {
+				if i6 == len(x.Pieces) {
+					return fmt.Errorf("expected list of %d elements", len(x.Pieces))
+				}
+				if err := d.Scan(&x.Pieces[i6]); err != nil {
+					return err
+				}
+				i6++
+				return nil
+			}); err != nil {
+				return err
+			}
+			if i6 != len(x.Pieces) {
+				return fmt.Errorf("expected list of %d elements, got %d", len(x.Pieces), i6)
+			}
+		case "eta":
+			if seen[13] {
+				return &rencode.FieldError{Path: "eta", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[13] = true
+			if none, err := d.DecodeNone(); err != nil {
+				return err
+			} else if none {
+				x.Eta = nil
+			} else {
+				x.Eta = new(int64)
+				if err := d.Scan(&(*x.Eta)); err != nil {
+					return err
+				}
+			}
+		case "extra":
+			if seen[14] {
+				return &rencode.FieldError{Path: "extra", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[14] = true
+			v7, err := d.DecodeNext()
+			if err != nil {
+				return err
+			}
+			x.Extra = v7
+		case "options":
+			if seen[15] {
+				return &rencode.FieldError{Path: "options", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[15] = true
+			if err := d.Scan(&x.Options); err != nil {
+				return err
+			}
+		case "big":
+			if seen[16] {
+				return &rencode.FieldError{Path: "big", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[16] = true
+			if err := d.Scan(&x.Big); err != nil {
+				return err
+			}
+		case "added":
+			if seen[17] {
+				return &rencode.FieldError{Path: "added", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[17] = true
+			if err := d.Scan(&x.Added); err != nil {
+				return err
+			}
+		case "interval":
+			if seen[18] {
+				return &rencode.FieldError{Path: "interval", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[18] = true
+			if none, err := d.DecodeNone(); err != nil {
+				return err
+			} else if none {
+				x.Interval = nil
+			} else {
+				x.Interval = new(time.Duration)
+				if err := d.Scan(&(*x.Interval)); err != nil {
+					return err
+				}
+			}
+		case "secret":
+			_, err := d.DecodeNext()
+			return err
+		default:
+			return &rencode.FieldError{Path: string(key), Err: rencode.ErrUnknownKey}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !seen[0] {
+		return &rencode.FieldError{Path: "name", Err: rencode.ErrMissingField}
+	}
+	if !seen[1] {
+		return &rencode.FieldError{Path: "state", Err: rencode.ErrMissingField}
+	}
+	if !seen[2] {
+		return &rencode.FieldError{Path: "progress", Err: rencode.ErrMissingField}
+	}
+	if !seen[3] {
+		return &rencode.FieldError{Path: "total_size", Err: rencode.ErrMissingField}
+	}
+	if !seen[4] {
+		return &rencode.FieldError{Path: "num_seeds", Err: rencode.ErrMissingField}
+	}
+	if !seen[5] {
+		return &rencode.FieldError{Path: "is_finished", Err: rencode.ErrMissingField}
+	}
+	if !seen[6] {
+		return &rencode.FieldError{Path: "hash", Err: rencode.ErrMissingField}
+	}
+	if !seen[8] {
+		x.MaxDownload = -1
+	}
+	if !seen[9] {
+		return &rencode.FieldError{Path: "trackers", Err: rencode.ErrMissingField}
+	}
+	if !seen[10] {
+		return &rencode.FieldError{Path: "peers", Err: rencode.ErrMissingField}
+	}
+	if !seen[11] {
+		return &rencode.FieldError{Path: "priorities", Err: rencode.ErrMissingField}
+	}
+	if !seen[12] {
+		return &rencode.FieldError{Path: "pieces", Err: rencode.ErrMissingField}
+	}
+	if !seen[13] {
+		return &rencode.FieldError{Path: "eta", Err: rencode.ErrMissingField}
+	}
+	if !seen[14] {
+		return &rencode.FieldError{Path: "extra", Err: rencode.ErrMissingField}
+	}
+	if !seen[15] {
+		return &rencode.FieldError{Path: "options", Err: rencode.ErrMissingField}
+	}
+	if !seen[16] {
+		return &rencode.FieldError{Path: "big", Err: rencode.ErrMissingField}
+	}
+	if !seen[17] {
+		return &rencode.FieldError{Path: "added", Err: rencode.ErrMissingField}
+	}
+	if !seen[18] {
+		return &rencode.FieldError{Path: "interval", Err: rencode.ErrMissingField}
+	}
+	return nil
+}
+
+// MarshalRencode encodes Tracker as a dictionary, like FromStruct
+func (x Tracker) MarshalRencode(e *rencode.Encoder) error {
+	term, err := e.BeginDict(2)
+	if err != nil {
+		return err
+	}
+	if err := e.Encode("url"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.URL); err != nil {
+		return err
+	}
+	if err := e.Encode("tier"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Tier); err != nil {
+		return err
+	}
+	if term {
+		return e.EndContainer()
+	}
+	return nil
+}
+
+// UnmarshalRencode decodes Tracker from a dictionary, like ToStruct
+func (x *Tracker) UnmarshalRencode(d *rencode.Decoder) error {
+	var seen [2]bool
+	err := d.DecodeDict(func() error {
+		var key []byte
+		if err := d.Scan(&key); err != nil {
+			return err
+		}
+		switch string(key) {
+		case "url":
+			if seen[0] {
+				return &rencode.FieldError{Path: "url", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[0] = true
+			if err := d.Scan(&x.URL); err != nil {
+				return err
+			}
+		case "tier":
+			if seen[1] {
+				return &rencode.FieldError{Path: "tier", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[1] = true
+			if err := d.Scan(&x.Tier); err != nil {
+				return err
+			}
+		default:
+			return &rencode.FieldError{Path: string(key), Err: rencode.ErrUnknownKey}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !seen[0] {
+		return &rencode.FieldError{Path: "url", Err: rencode.ErrMissingField}
+	}
+	if !seen[1] {
+		return &rencode.FieldError{Path: "tier", Err: rencode.ErrMissingField}
+	}
+	return nil
+}
+
+// MarshalRencode encodes Peer as a dictionary, like FromStruct
+func (x Peer) MarshalRencode(e *rencode.Encoder) error {
+	term, err := e.BeginDict(3)
+	if err != nil {
+		return err
+	}
+	if err := e.Encode("ip"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.IP); err != nil {
+		return err
+	}
+	if err := e.Encode("port"); err != nil {
+		return err
+	}
+	if err := e.Encode(x.Port); err != nil {
+		return err
+	}
+	if err := e.Encode("client"); err != nil {
+		return err
+	}
+	if x.Client == nil {
+		if err := e.EncodeNone(); err != nil {
+			return err
+		}
+	} else {
+		if err := e.Encode((*x.Client)); err != nil {
+			return err
+		}
+	}
+	if term {
+		return e.EndContainer()
+	}
+	return nil
+}
+
+// UnmarshalRencode decodes Peer from a dictionary, like ToStruct
+func (x *Peer) UnmarshalRencode(d *rencode.Decoder) error {
+	var seen [3]bool
+	err := d.DecodeDict(func() error {
+		var key []byte
+		if err := d.Scan(&key); err != nil {
+			return err
+		}
+		switch string(key) {
+		case "ip":
+			if seen[0] {
+				return &rencode.FieldError{Path: "ip", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[0] = true
+			if err := d.Scan(&x.IP); err != nil {
+				return err
+			}
+		case "port":
+			if seen[1] {
+				return &rencode.FieldError{Path: "port", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[1] = true
+			if err := d.Scan(&x.Port); err != nil {
+				return err
+			}
+		case "client":
+			if seen[2] {
+				return &rencode.FieldError{Path: "client", Err: rencode.ErrKeyAlreadyExists}
+			}
+			seen[2] = true
+			if none, err := d.DecodeNone(); err != nil {
+				return err
+			} else if none {
+				x.Client = nil
+			} else {
+				x.Client = new(string)
+				if err := d.Scan(&(*x.Client)); err != nil {
+					return err
+				}
+			}
+		default:
+			return &rencode.FieldError{Path: string(key), Err: rencode.ErrUnknownKey}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !seen[0] {
+		return &rencode.FieldError{Path: "ip", Err: rencode.ErrMissingField}
+	}
+	if !seen[1] {
+		return &rencode.FieldError{Path: "port", Err: rencode.ErrMissingField}
+	}
+	if !seen[2] {
+		return &rencode.FieldError{Path: "client", Err: rencode.ErrMissingField}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package gentest
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func newStatus() Status {
+	client := "qBittorrent"
+	eta := int64(3600)
+	var options rencode.Dictionary
+	options.Add([]byte("max_connections"), int8(50))
+
+	s := Status{
+		Name:        "ubuntu.iso",
+		State:       "Downloading",
+		Progress:    42.5,
+		TotalSize:   2715254784,
+		NumSeeds:    12,
+		Hash:        []byte("0123456789abcdef0123"),
+		MaxDownload: 100,
+		Trackers:    []Tracker{{"https://torrent.ubuntu.com/announce", 0}, {"udp://t", 1}},
+		Peers: map[string]*Peer{
+			"b": {IP: "10.0.0.2", Port: 51413},
+			"a": {IP: "10.0.0.1", Port: 6881, Client: &client},
+			"c": nil,
+		},
+		Priorities: Priorities{1, 0, 7},
+		Pieces:     [2]bool{true, false},
+		Eta:        &eta,
+		Extra:      rencode.NewList([]byte("x"), int8(1)),
+		Options:    options,
+	}
+	s.Big.SetString("123456789012345678901234567890", 10)
+	s.Added = time.Unix(1600000000, 500000000)
+	interval := 90 * time.Second
+	s.Interval = &interval
+	return s
+}
+
+func TestGeneratedMatchesReflection(t *testing.T) {
+	t.Parallel()
+
+	s := newStatus()
+	var generated bytes.Buffer
+	e := rencode.NewEncoder(&generated)
+	err := e.Encode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := rencode.FromStruct(s, "private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reflected bytes.Buffer
+	e = rencode.NewEncoder(&reflected)
+	err = e.Encode(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated.Bytes(), reflected.Bytes()) {
+		t.Fatalf("generated encoding differs from FromStruct:\n%q\n%q", generated.Bytes(), reflected.Bytes())
+	}
+
+	var decoded Status
+	dec := rencode.NewDecoder(bytes.NewReader(generated.Bytes()))
+	err = dec.Scan(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, decoded) {
+		t.Errorf("generated decoding mismatch:\n%+v\n%+v", s, decoded)
+	}
+
+	var viaToStruct Status
+	err = d.ToStruct(&viaToStruct, "private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, viaToStruct) {
+		t.Errorf("generated decoding differs from ToStruct:\n%+v\n%+v", decoded, viaToStruct)
+	}
+}
+
+func TestGeneratedOptionsAndErrors(t *testing.T) {
+	t.Parallel()
+
+	decode := func(text string) (Status, error) {
+		v, err := rencode.ParseText(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		e := rencode.NewEncoder(&b)
+		if err := e.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+		var s Status
+		s.Label = "keep"
+		err = rencode.NewDecoder(&b).Scan(&s)
+		return s, err
+	}
+
+	const fields = `'name': 'a', 'state': 'Seeding', 'progress': 100.0, 'total_size': 1, 'num_seeds': 0,
+		'is_finished': True, 'hash': b'', 'trackers': [], 'peers': {}, 'priorities': [],
+		'eta': None, 'extra': None, 'options': {}, 'big': 123456789012345678901234567890,
+		'added': 1600000000.25, 'interval': None`
+
+	s, err := decode("{" + fields + ", 'pieces': [False, False], 'secret': 'ignored'}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Label != "keep" || s.MaxDownload != -1 || s.Eta != nil || s.Secret != "" || !s.Added.Equal(time.Unix(1600000000, 250000000)) || s.Big.String() != "123456789012345678901234567890" {
+		t.Errorf("unexpected status %+v", s)
+	}
+
+	_, err = decode("{" + fields + ", 'pieces': [False, False], 'unknown': 1}")
+	if !errors.Is(err, rencode.ErrUnknownKey) {
+		t.Errorf("expected unknown key error but got %v", err)
+	}
+	_, err = decode("{'name': 'a'}")
+	if !errors.Is(err, rencode.ErrMissingField) {
+		t.Errorf("expected missing field error but got %v", err)
+	}
+	_, err = decode("{" + fields + ", 'pieces': [True]}")
+	if err == nil {
+		t.Error("expected failure for array length mismatch")
+	}
+}
+
+func BenchmarkUnmarshalGenerated(b *testing.B) {
+	s := newStatus()
+	var buf bytes.Buffer
+	e := rencode.NewEncoder(&buf)
+	if err := e.Encode(s); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded Status
+		if err := decoded.UnmarshalRencode(rencode.NewDecoder(bytes.NewReader(data))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalToStruct(b *testing.B) {
+	s := newStatus()
+	d, err := rencode.FromStruct(s, "private")
+	if err != nil {
+		b.Fatal(err)
+	}
+	var buf bytes.Buffer
+	e := rencode.NewEncoder(&buf)
+	if err := e.Encode(d); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded Status
+		var d rencode.Dictionary
+		if err := rencode.NewDecoder(bytes.NewReader(data)).Scan(&d); err != nil {
+			b.Fatal(err)
+		}
+		if err := d.ToStruct(&decoded, "private"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalGenerated(b *testing.B) {
+	s := newStatus()
+	var buf bytes.Buffer
+	e := rencode.NewEncoder(&buf)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := s.MarshalRencode(&e); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalFromStruct(b *testing.B) {
+	s := newStatus()
+	var buf bytes.Buffer
+	e := rencode.NewEncoder(&buf)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		d, err := rencode.FromStruct(s, "private")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := e.Encode(d); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
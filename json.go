@@ -0,0 +1,525 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+)
+
+/*
+The tagged JSON representation used by ToJSON and FromJSON maps every rencode value to a JSON
+object with exactly one of the following type keys:
+
+	{"none": null}
+	{"bool": true}
+	{"int8": 1}, {"int16": 1}, {"int32": 1}, {"int64": 1}
+	{"bigint": "18446744073709551616"}
+	{"float32": 1.5}, {"float64": 1.5}   also "NaN", "+Inf" and "-Inf"
+	{"text": "hello"}                    byte string which is valid UTF-8
+	{"bytes": "AAEC"}                    any other byte string, base64-encoded
+	{"list": [...]}
+	{"dict": [[key, value], ...]}        keys can be of any type
+
+FromJSON also accepts the typed annotations {"tuple": [...]} and {"set": [...]} as input only, for
+documents written by hand or by other tools; the elements of a set must be distinct values which
+can be elements of a Set. Both are encoded as lists, since rencode has no tuple or set type, and
+ToJSON always writes lists.
+
+The integer keys correspond to the rencode type codes used on the wire; values which have not
+been encoded in the most compact form also carry an "enc" key:
+
+	"int1"        integer in the embedded range encoded with CHR_INT1
+	"prefixed"    short byte string encoded with a length prefix
+	"terminated"  short list or dictionary encoded with a terminator
+
+The digits of big integers are normalized; any other encoding round-trips byte-for-byte.
+*/
+
+// JSON encoding forms of values not encoded in their most compact form
+const (
+	jsonEncInt1       = "int1"
+	jsonEncPrefixed   = "prefixed"
+	jsonEncTerminated = "terminated"
+)
+
+// ToJSON converts all the values of the rencode stream read from r to the tagged JSON representation,
+// writing one JSON document per line to w.
+func ToJSON(w io.Writer, r io.Reader) error {
+	d := NewDecoder(r)
+	bw := bufio.NewWriter(w)
+
+	for {
+		t, err := d.NextToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tokenToJSON(bw, d, t)
+		if err != nil {
+			return err
+		}
+		err = bw.WriteByte('\n')
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func nextInnerToken(d *Decoder) (Token, error) {
+	t, err := d.NextToken()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return t, err
+}
+
+func writeJSONString(w *bufio.Writer, s string) error {
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	err := e.Encode(s)
+	if err != nil {
+		return err
+	}
+	// remove trailing newline
+	_, err = w.Write(b.Bytes()[:b.Len()-1])
+	return err
+}
+
+func formatJSONFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return `"NaN"`
+	case math.IsInf(f, 1):
+		return `"+Inf"`
+	case math.IsInf(f, -1):
+		return `"-Inf"`
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+func tokenToJSON(w *bufio.Writer, d *Decoder, t Token) error {
+	switch t.Kind {
+	case EndToken:
+		return fmt.Errorf("unexpected terminator at offset %d", t.Offset)
+	case ListToken:
+		w.WriteString(`{"list":[`)
+		n := 0
+		for ; t.Length < 0 || n < t.Length; n++ {
+			c, err := nextInnerToken(d)
+			if err != nil {
+				return err
+			}
+			if c.Kind == EndToken && t.Length < 0 {
+				break
+			}
+			if n > 0 {
+				w.WriteByte(',')
+			}
+			err = tokenToJSON(w, d, c)
+			if err != nil {
+				return err
+			}
+		}
+		w.WriteByte(']')
+		if t.Length < 0 && n < LIST_FIXED_COUNT {
+			w.WriteString(`,"enc":"` + jsonEncTerminated + `"`)
+		}
+		_, err := w.WriteString("}")
+		return err
+	case DictToken:
+		w.WriteString(`{"dict":[`)
+		n := 0
+		for ; t.Length < 0 || n < t.Length; n++ {
+			c, err := nextInnerToken(d)
+			if err != nil {
+				return err
+			}
+			if c.Kind == EndToken && t.Length < 0 {
+				break
+			}
+			if n > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteByte('[')
+			err = tokenToJSON(w, d, c)
+			if err != nil {
+				return err
+			}
+
+			c, err = nextInnerToken(d)
+			if err != nil {
+				return err
+			}
+			if c.Kind == EndToken && t.Length < 0 {
+				// key without value right before the terminator
+				w.WriteString("]")
+				n++
+				break
+			}
+			w.WriteByte(',')
+			err = tokenToJSON(w, d, c)
+			if err != nil {
+				return err
+			}
+			w.WriteByte(']')
+		}
+		w.WriteByte(']')
+		if t.Length < 0 && n < DICT_FIXED_COUNT {
+			w.WriteString(`,"enc":"` + jsonEncTerminated + `"`)
+		}
+		_, err := w.WriteString("}")
+		return err
+	}
+
+	var enc string
+	switch v := t.Value.(type) {
+	case nil:
+		w.WriteString(`{"none":null`)
+	case bool:
+		fmt.Fprintf(w, `{"bool":%v`, v)
+	case int8:
+		fmt.Fprintf(w, `{"int8":%d`, v)
+		if t.Code == CHR_INT1 && -INT_NEG_FIXED_COUNT <= v && v < INT_POS_FIXED_COUNT {
+			enc = jsonEncInt1
+		}
+	case int16:
+		fmt.Fprintf(w, `{"int16":%d`, v)
+	case int32:
+		fmt.Fprintf(w, `{"int32":%d`, v)
+	case int64:
+		fmt.Fprintf(w, `{"int64":%d`, v)
+	case big.Int:
+		fmt.Fprintf(w, `{"bigint":"%s"`, v.String())
+	case float32:
+		fmt.Fprintf(w, `{"float32":%s`, formatJSONFloat(float64(v), 32))
+	case float64:
+		fmt.Fprintf(w, `{"float64":%s`, formatJSONFloat(v, 64))
+	case []byte:
+		if utf8.Valid(v) {
+			w.WriteString(`{"text":`)
+			err := writeJSONString(w, string(v))
+			if err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintf(w, `{"bytes":"%s"`, base64.StdEncoding.EncodeToString(v))
+		}
+		if t.Code < STR_FIXED_START && len(v) < STR_FIXED_COUNT {
+			enc = jsonEncPrefixed
+		}
+	default:
+		return fmt.Errorf("unexpected value of type %T at offset %d", v, t.Offset)
+	}
+
+	if enc != "" {
+		w.WriteString(`,"enc":"` + enc + `"`)
+	}
+	_, err := w.WriteString("}")
+	return err
+}
+
+// FromJSON converts all the JSON documents read from r, which must use the tagged JSON representation
+// produced by ToJSON, to rencode values written to w.
+func FromJSON(r io.Reader, w io.Writer) error {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	e := NewEncoder(w)
+
+	for {
+		var v interface{}
+		err := d.Decode(&v)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = e.encodeJSON(v)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func parseJSONFloat(v interface{}, bitSize int) (float64, error) {
+	switch x := v.(type) {
+	case json.Number:
+		return strconv.ParseFloat(string(x), bitSize)
+	case string:
+		switch x {
+		case "NaN":
+			return math.NaN(), nil
+		case "+Inf":
+			return math.Inf(1), nil
+		case "-Inf":
+			return math.Inf(-1), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid float value %v", v)
+}
+
+func parseJSONInt(v interface{}, bitSize int) (int64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid integer value %v", v)
+	}
+	return strconv.ParseInt(string(n), 10, bitSize)
+}
+
+func (r *Encoder) encodeJSON(v interface{}) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected tagged JSON object, got %v", v)
+	}
+
+	var enc string
+	keys := 1
+	if e, ok := obj["enc"]; ok {
+		enc, _ = e.(string)
+		switch enc {
+		case jsonEncInt1, jsonEncPrefixed, jsonEncTerminated:
+		default:
+			return fmt.Errorf("invalid encoding form %v", e)
+		}
+		keys++
+	}
+	if len(obj) != keys {
+		return fmt.Errorf("expected exactly one type key, got %v", obj)
+	}
+
+	for tag, x := range obj {
+		if tag == "enc" {
+			continue
+		}
+
+		switch tag {
+		case "none":
+			if x != nil {
+				return fmt.Errorf("invalid none value %v", x)
+			}
+			return r.EncodeNone()
+		case "bool":
+			b, ok := x.(bool)
+			if !ok {
+				return fmt.Errorf("invalid bool value %v", x)
+			}
+			return r.EncodeBool(b)
+		case "int8":
+			i, err := parseJSONInt(x, 8)
+			if err != nil {
+				return err
+			}
+			if enc == jsonEncInt1 {
+				_, err = r.w.Write([]byte{CHR_INT1, byte(i)})
+				return err
+			}
+			return r.EncodeInt8(int8(i))
+		case "int16":
+			i, err := parseJSONInt(x, 16)
+			if err != nil {
+				return err
+			}
+			return r.EncodeInt16(int16(i))
+		case "int32":
+			i, err := parseJSONInt(x, 32)
+			if err != nil {
+				return err
+			}
+			return r.EncodeInt32(int32(i))
+		case "int64":
+			i, err := parseJSONInt(x, 64)
+			if err != nil {
+				return err
+			}
+			return r.EncodeInt64(i)
+		case "bigint":
+			s, ok := x.(string)
+			if !ok {
+				return fmt.Errorf("invalid bigint value %v", x)
+			}
+			var i big.Int
+			if _, ok := i.SetString(s, 10); !ok {
+				return fmt.Errorf("invalid bigint value %q", s)
+			}
+			return r.encodeSingle(i)
+		case "float32":
+			f, err := parseJSONFloat(x, 32)
+			if err != nil {
+				return err
+			}
+			return r.EncodeFloat32(float32(f))
+		case "float64":
+			f, err := parseJSONFloat(x, 64)
+			if err != nil {
+				return err
+			}
+			return r.EncodeFloat64(f)
+		case "text", "bytes":
+			s, ok := x.(string)
+			if !ok {
+				return fmt.Errorf("invalid %s value %v", tag, x)
+			}
+			b := []byte(s)
+			if tag == "bytes" {
+				var err error
+				b, err = base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					return err
+				}
+			}
+			if enc == jsonEncPrefixed {
+				_, err := fmt.Fprintf(r.w, "%d:", len(b))
+				if err != nil {
+					return err
+				}
+				_, err = r.w.Write(b)
+				return err
+			}
+			return r.EncodeBytes(b)
+		case "list", "tuple", "set":
+			values, ok := x.([]interface{})
+			if !ok {
+				return fmt.Errorf("invalid %s value %v", tag, x)
+			}
+			if tag == "set" {
+				err := checkJSONSet(values)
+				if err != nil {
+					return err
+				}
+			}
+			n := len(values)
+			if enc == jsonEncTerminated {
+				n = -1
+			}
+			terminated, err := r.BeginList(n)
+			if err != nil {
+				return err
+			}
+			for _, value := range values {
+				err = r.encodeJSON(value)
+				if err != nil {
+					return err
+				}
+			}
+			if terminated {
+				return r.EndContainer()
+			}
+			return nil
+		case "dict":
+			pairs, ok := x.([]interface{})
+			if !ok {
+				return fmt.Errorf("invalid dict value %v", x)
+			}
+			n := len(pairs)
+			if enc == jsonEncTerminated {
+				n = -1
+			}
+			terminated, err := r.BeginDict(n)
+			if err != nil {
+				return err
+			}
+			for i, p := range pairs {
+				pair, ok := p.([]interface{})
+				// a key without value is allowed only as last element of a terminated dictionary
+				if !ok || len(pair) == 0 || len(pair) > 2 || (len(pair) == 1 && (!terminated || i != len(pairs)-1)) {
+					return fmt.Errorf("invalid dict pair %v", p)
+				}
+				for _, value := range pair {
+					err = r.encodeJSON(value)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			if terminated {
+				return r.EndContainer()
+			}
+			return nil
+		}
+
+		return fmt.Errorf("unknown type key %q", tag)
+	}
+
+	panic("unexpected fallthrough")
+}
+
+// checkJSONSet returns an error if the tagged JSON values are not distinct elements of a Set
+func checkJSONSet(values []interface{}) error {
+	var s Set
+	for _, value := range values {
+		v, err := jsonSetElement(value)
+		if err != nil {
+			return err
+		}
+		if s.Has(v) {
+			return fmt.Errorf("duplicate set element %s", FormatText(v))
+		}
+		err = s.Add(v)
+		if err != nil {
+			return fmt.Errorf("set element %s: %v", FormatText(v), err)
+		}
+	}
+	return nil
+}
+
+// jsonSetElement returns the value of a tagged JSON set element, with tuples as Tuple
+func jsonSetElement(v interface{}) (interface{}, error) {
+	if obj, ok := v.(map[string]interface{}); ok && len(obj) == 1 {
+		if x, ok := obj["tuple"]; ok {
+			values, ok := x.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid tuple value %v", x)
+			}
+			t := NewTuple()
+			for _, value := range values {
+				e, err := jsonSetElement(value)
+				if err != nil {
+					return nil, err
+				}
+				t.Add(e)
+			}
+			return t, nil
+		}
+	}
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.encodeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewDecoder(&b).DecodeNext()
+}
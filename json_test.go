@@ -0,0 +1,126 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	t.Parallel()
+
+	var d Dictionary
+	d.Add("alpha", NewList(int8(1), nil, true))
+	d.Add(int16(300), []byte{0xff, 0x00})
+	d.Add(false, float32(1.5))
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(d, math.Inf(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = ToJSON(&out, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"dict":[[{"text":"alpha"},{"list":[{"int8":1},{"none":null},{"bool":true}]}],[{"int16":300},{"bytes":"/wA="}],[{"bool":false},{"float32":1.5}]]}
+{"float64":"-Inf"}
+`
+	if out.String() != expected {
+		t.Errorf("unexpected JSON:\n%s", out.String())
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var value big.Int
+	value.SetUint64(^uint64(0))
+	value.Mul(&value, big.NewInt(32))
+
+	var long List
+	for i := 0; i < 100; i++ {
+		long.Add(i)
+	}
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(int8(-100), int16(5), int32(7483648), int64(-8223372036854775808), value,
+		float32(1234.56), 1234.56, math.NaN(), "fööbar", strings.Repeat("f", 255), long)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// values not encoded in their most compact form
+	b.Write([]byte{CHR_INT1, 5})
+	b.Write([]byte("3:abc"))
+	b.Write([]byte{CHR_LIST, 1, CHR_TERM})
+	b.Write([]byte{CHR_DICT, STR_FIXED_START + 1, 'a', 2, STR_FIXED_START + 1, 'b', CHR_TERM})
+
+	original := b.Bytes()
+
+	var j bytes.Buffer
+	err = ToJSON(&j, bytes.NewReader(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result bytes.Buffer
+	err = FromJSON(&j, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(original, result.Bytes()) {
+		t.Fatalf("round-trip mismatch:\n%v\n%v", original, result.Bytes())
+	}
+}
+
+func TestFromJSONInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		`1`,
+		`{"int8":300}`,
+		`{"int8":1,"int16":1}`,
+		`{"list":[1]}`,
+		`{"dict":[[{"int8":1}]]}`,
+		`{"bytes":"!"}`,
+		`{"unknown":1}`,
+		`{"enc":""}`,
+		`{"enc":"int1"}`,
+		`{"enc":"other","int8":1}`,
+		`{"enc":1,"int8":1}`,
+	} {
+		var b bytes.Buffer
+		err := FromJSON(strings.NewReader(input), &b)
+		if err == nil {
+			t.Errorf("expected failure for %s", input)
+		}
+	}
+}
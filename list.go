@@ -21,14 +21,19 @@ package rencode
 
 import (
 	"errors"
+	"sort"
 )
 
 var (
 	// ErrKeyNotFound is the error returned when specified key does not exist in List or Dictionary
 	ErrKeyNotFound = errors.New("key not found")
+	// ErrIndexOutOfRange is the error returned when the specified index or range is out of the List bounds
+	ErrIndexOutOfRange = errors.New("index out of range")
 )
 
-// List is a rencode-specific list that allows any type of value to be concatenated
+// List is a rencode-specific list that allows any type of value to be concatenated.
+// Like a slice, a copy of a list shares its storage with the original and the methods modify it
+// in place, so only one of them can be modified; use Clone to obtain an independent list.
 type List struct {
 	values []interface{}
 }
@@ -52,3 +57,92 @@ func (l *List) Values() []interface{} {
 func (l *List) Length() int {
 	return len(l.values)
 }
+
+// Get returns the value at the specified index
+func (l *List) Get(i int) (interface{}, error) {
+	if i < 0 || i >= len(l.values) {
+		return nil, ErrIndexOutOfRange
+	}
+	return l.values[i], nil
+}
+
+// Set replaces the value at the specified index
+func (l *List) Set(i int, value interface{}) error {
+	if i < 0 || i >= len(l.values) {
+		return ErrIndexOutOfRange
+	}
+	l.values[i] = value
+	return nil
+}
+
+// Insert inserts one or more values before the specified index; an index equal to
+// the length of the list appends the values.
+func (l *List) Insert(i int, values ...interface{}) error {
+	if i < 0 || i > len(l.values) {
+		return ErrIndexOutOfRange
+	}
+
+	// the inserted values might be part of the list itself
+	values = append([]interface{}(nil), values...)
+	n := len(l.values)
+	l.values = append(l.values, values...)
+	copy(l.values[i+len(values):], l.values[i:n])
+	copy(l.values[i:], values)
+	return nil
+}
+
+// Remove removes and returns the value at the specified index
+func (l *List) Remove(i int) (interface{}, error) {
+	if i < 0 || i >= len(l.values) {
+		return nil, ErrIndexOutOfRange
+	}
+
+	v := l.values[i]
+	last := len(l.values) - 1
+	copy(l.values[i:], l.values[i+1:])
+	// clear the removed value, which can then be garbage collected
+	l.values[last] = nil
+	l.values = l.values[:last]
+	return v, nil
+}
+
+// Pop removes and returns the last value of the list; it does not move any other value.
+func (l *List) Pop() (interface{}, error) {
+	return l.Remove(len(l.values) - 1)
+}
+
+// Slice returns a new list with the values from index 'from' (included) to 'to' (excluded)
+func (l *List) Slice(from, to int) (List, error) {
+	if from < 0 || to > len(l.values) || from > to {
+		return List{}, ErrIndexOutOfRange
+	}
+	return NewList(append([]interface{}(nil), l.values[from:to]...)...), nil
+}
+
+// Range calls f for each value in order, until f returns false.
+func (l *List) Range(f func(i int, value interface{}) bool) {
+	for i, v := range l.values {
+		if !f(i, v) {
+			return
+		}
+	}
+}
+
+// Reverse reverses the order of the values
+func (l *List) Reverse() {
+	for i, j := 0, len(l.values)-1; i < j; i, j = i+1, j-1 {
+		l.values[i], l.values[j] = l.values[j], l.values[i]
+	}
+}
+
+// Clone returns a deep copy of the list; nested containers, big integers and byte slices are copied too.
+func (l *List) Clone() List {
+	return cloneValue(*l).(List)
+}
+
+// Sort sorts the values with the specified less function; the sort is stable.
+func (l *List) Sort(less func(a, b interface{}) bool) {
+	sort.SliceStable(l.values, func(i, j int) bool {
+		return less(l.values[i], l.values[j])
+	})
+}
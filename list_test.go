@@ -83,26 +83,7 @@ func listCompareVerbose(t *testing.T, a, b *List) bool {
 		t.Errorf("list length mismatch: %v != %v", a.Length(), b.Length())
 		return false
 	}
-
-	matching := true
-	for i, aV := range a.Values() {
-		bV := b.Values()[i]
-
-		// normalize both values to string if they are of []byte type
-		if v, ok := aV.([]byte); ok {
-			aV = string(v)
-		}
-		if v, ok := bV.([]byte); ok {
-			bV = string(v)
-		}
-
-		if aV != bV {
-			t.Errorf("index %d: expected %q (type %T) but %q (type %T) found", i, aV, aV, bV, bV)
-			matching = false
-		}
-	}
-
-	return matching
+	return compareVerbose(t, *a, *b)
 }
 
 func TestListScanAndShift(t *testing.T) {
@@ -141,3 +122,111 @@ func TestListScanAndShift(t *testing.T) {
 		t.Fatal("could not read third value of the list after shift")
 	}
 }
+
+func TestListSequence(t *testing.T) {
+	t.Parallel()
+
+	l := NewList(int8(1), int8(2), int8(3))
+	err := l.Insert(1, "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = l.Insert(l.Length(), "end")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = l.Set(0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := l.Remove(2)
+	if err != nil || v != "b" {
+		t.Fatalf("unexpected removed value %v (%v)", v, err)
+	}
+	v, err = l.Pop()
+	if err != nil || v != "end" {
+		t.Fatalf("unexpected popped value %v (%v)", v, err)
+	}
+	if FormatText(l) != "[None, 'a', 2, 3]" {
+		t.Fatalf("unexpected list %s", FormatText(l))
+	}
+
+	s, err := l.Slice(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Reverse()
+	if FormatText(s) != "[2, 'a']" || FormatText(l) != "[None, 'a', 2, 3]" {
+		t.Errorf("unexpected slice %s of %s", FormatText(s), FormatText(l))
+	}
+
+	count := 0
+	l.Range(func(i int, v interface{}) bool {
+		count++
+		return i < 1
+	})
+	if count != 2 {
+		t.Errorf("expected Range to stop after 2 values but got %d", count)
+	}
+
+	n := NewList(int8(3), int8(1), int8(2))
+	n.Sort(func(a, b interface{}) bool {
+		return a.(int8) < b.(int8)
+	})
+	if FormatText(n) != "[1, 2, 3]" {
+		t.Errorf("unexpected sorted list %s", FormatText(n))
+	}
+
+	// the values of the list itself can be inserted
+	err = n.Insert(1, n.Values()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(n) != "[1, 1, 2, 3, 2, 3]" {
+		t.Errorf("unexpected list %s", FormatText(n))
+	}
+
+	// popping does not copy the list
+	first := &n.Values()[0]
+	for n.Length() > 1 {
+		n.Pop()
+	}
+	if &n.Values()[0] != first {
+		t.Error("list copied when popping values")
+	}
+}
+
+func TestListOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	var l List
+	if _, err := l.Get(0); err != ErrIndexOutOfRange {
+		t.Errorf("expected ErrIndexOutOfRange but got %v", err)
+	}
+	if _, err := l.Pop(); err != ErrIndexOutOfRange {
+		t.Errorf("expected ErrIndexOutOfRange but got %v", err)
+	}
+	if err := l.Insert(1, true); err != ErrIndexOutOfRange {
+		t.Errorf("expected ErrIndexOutOfRange but got %v", err)
+	}
+	if err := l.Set(-1, true); err != ErrIndexOutOfRange {
+		t.Errorf("expected ErrIndexOutOfRange but got %v", err)
+	}
+	if _, err := l.Slice(0, 1); err != ErrIndexOutOfRange {
+		t.Errorf("expected ErrIndexOutOfRange but got %v", err)
+	}
+}
+
+func TestListClone(t *testing.T) {
+	t.Parallel()
+
+	l := NewList([]byte("x"), NewList([]byte("y")))
+	c := l.Clone()
+	c.Values()[0].([]byte)[0] = 'z'
+	nested := c.Values()[1].(List)
+	nested.Values()[0].([]byte)[0] = 'z'
+
+	if FormatText(l) != "[b'x', [b'y']]" {
+		t.Errorf("original list modified: %s", FormatText(l))
+	}
+}
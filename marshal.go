@@ -0,0 +1,100 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"fmt"
+	"io"
+)
+
+// Marshaler is implemented by types which encode themselves, like those generated by rencodegen;
+// Encode uses it for values which are not of one of the supported types.
+type Marshaler interface {
+	MarshalRencode(e *Encoder) error
+}
+
+// Unmarshaler is implemented by types which decode themselves from the next value of the stream,
+// like those generated by rencodegen; Scan uses it for targets implementing it.
+type Unmarshaler interface {
+	UnmarshalRencode(d *Decoder) error
+}
+
+// DecodeNone consumes the next value and returns true if it is None; otherwise the value is left
+// in the stream, to be decoded by the following call.
+func (r *Decoder) DecodeNone() (bool, error) {
+	typeCode, err := r.readByte()
+	if err != nil {
+		return false, err
+	}
+	if typeCode == CHR_NONE {
+		return true, nil
+	}
+	r.unreadByte(typeCode)
+	return false, nil
+}
+
+// DecodeList reads the start of a list and calls fn once for each element, which fn must consume.
+func (r *Decoder) DecodeList(fn func() error) error {
+	return r.decodeContainer(ListToken, fn)
+}
+
+// DecodeDict reads the start of a dictionary and calls fn once for each (key, value) pair,
+// which fn must consume.
+func (r *Decoder) DecodeDict(fn func() error) error {
+	return r.decodeContainer(DictToken, fn)
+}
+
+func (r *Decoder) decodeContainer(kind TokenKind, fn func() error) error {
+	t, err := r.NextToken()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if t.Kind != kind {
+		if kind == ListToken {
+			return fmt.Errorf("expected list at offset %d", t.Offset)
+		}
+		return fmt.Errorf("expected dictionary at offset %d", t.Offset)
+	}
+
+	for i := 0; t.Length < 0 || i < t.Length; i++ {
+		if t.Length < 0 {
+			typeCode, err := r.readByte()
+			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return err
+			}
+			if typeCode == CHR_TERM {
+				break
+			}
+			r.unreadByte(typeCode)
+		}
+
+		err = fn()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,400 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"unicode/utf8"
+)
+
+/*
+MessagePack mapping used by TranscodeToMsgpack and TranscodeFromMsgpack:
+
+	rencode              MessagePack
+	-------              -----------
+	None                 nil
+	bool                 bool
+	int8                 positive/negative fixint or int 8
+	int16, int32, int64  int 16, int 32, int 64
+	big.Int              int 64 or uint 64; error if the value needs more than 64 bits
+	float32, float64     float 32, float 64
+	byte string          str if valid UTF-8, bin otherwise
+	list                 array
+	dictionary           map
+
+Lossy cases when converting from MessagePack: str and bin both become byte strings; uint 8/16/32
+become the narrowest signed integer type holding the value and uint 64 values larger than the
+maximum int64 become big integers. Extension types (including timestamps) have no rencode
+representation and are reported as errors.
+*/
+
+// msgpackWriter transcodes the rencode token stream to MessagePack
+type msgpackWriter struct {
+	w io.Writer
+}
+
+// TranscodeToMsgpack converts all the values of the rencode stream read from r to MessagePack values written to w
+func TranscodeToMsgpack(w io.Writer, r io.Reader) error {
+	d := NewDecoder(r)
+	m := msgpackWriter{w}
+	for {
+		t, err := d.NextToken()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = m.token(d, t)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (m msgpackWriter) write(b ...byte) error {
+	_, err := m.w.Write(b)
+	return err
+}
+
+func (m msgpackWriter) writeUint(code byte, size int, v uint64) error {
+	b := make([]byte, 1+size)
+	b[0] = code
+	switch size {
+	case 1:
+		b[1] = byte(v)
+	case 2:
+		binary.BigEndian.PutUint16(b[1:], uint16(v))
+	case 4:
+		binary.BigEndian.PutUint32(b[1:], uint32(v))
+	case 8:
+		binary.BigEndian.PutUint64(b[1:], v)
+	}
+	_, err := m.w.Write(b)
+	return err
+}
+
+// header writes the header of a str, bin, array or map; codes are for the fixed, 8, 16 and 32-bit forms
+func (m msgpackWriter) header(n int, fixed byte, fixedMax int, code8, code16, code32 byte) error {
+	switch {
+	case n <= fixedMax:
+		return m.write(fixed | byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return m.writeUint(code8, 1, uint64(n))
+	case n <= math.MaxUint16:
+		return m.writeUint(code16, 2, uint64(n))
+	}
+	return m.writeUint(code32, 4, uint64(n))
+}
+
+// container transcodes the elements of a list (pairs = 1) or dictionary (pairs = 2)
+func (m msgpackWriter) container(d *Decoder, t Token, pairs int) error {
+	fixed, fixedMax, code16, code32 := byte(0x90), 15, byte(0xdc), byte(0xdd)
+	if pairs == 2 {
+		fixed, code16, code32 = 0x80, 0xde, 0xdf
+	}
+
+	if t.Length >= 0 {
+		err := m.header(t.Length, fixed, fixedMax, 0, code16, code32)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < t.Length*pairs; i++ {
+			c, err := nextInnerToken(d)
+			if err != nil {
+				return err
+			}
+			err = m.token(d, c)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// the count of elements is known only at the end, buffer them
+	var b bytes.Buffer
+	inner := msgpackWriter{&b}
+	n := 0
+	for ; ; n++ {
+		c, err := nextInnerToken(d)
+		if err != nil {
+			return err
+		}
+		if c.Kind == EndToken {
+			if n%pairs != 0 {
+				return fmt.Errorf("dictionary key without value at offset %d", c.Offset)
+			}
+			break
+		}
+		err = inner.token(d, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := m.header(n/pairs, fixed, fixedMax, 0, code16, code32)
+	if err != nil {
+		return err
+	}
+	_, err = b.WriteTo(m.w)
+	return err
+}
+
+func (m msgpackWriter) token(d *Decoder, t Token) error {
+	switch t.Kind {
+	case EndToken:
+		return fmt.Errorf("unexpected terminator at offset %d", t.Offset)
+	case ListToken:
+		return m.container(d, t, 1)
+	case DictToken:
+		return m.container(d, t, 2)
+	}
+
+	switch v := t.Value.(type) {
+	case nil:
+		return m.write(0xc0)
+	case bool:
+		if v {
+			return m.write(0xc3)
+		}
+		return m.write(0xc2)
+	case int8:
+		// positive and negative fixint
+		if v >= -32 {
+			return m.write(byte(v))
+		}
+		return m.write(0xd0, byte(v))
+	case int16:
+		return m.writeUint(0xd1, 2, uint64(v))
+	case int32:
+		return m.writeUint(0xd2, 4, uint64(v))
+	case int64:
+		return m.writeUint(0xd3, 8, uint64(v))
+	case big.Int:
+		if v.IsInt64() {
+			return m.writeUint(0xd3, 8, uint64(v.Int64()))
+		}
+		if v.IsUint64() {
+			return m.writeUint(0xcf, 8, v.Uint64())
+		}
+		return fmt.Errorf("integer %s at offset %d has no MessagePack representation", v.String(), t.Offset)
+	case float32:
+		return m.writeUint(0xca, 4, uint64(math.Float32bits(v)))
+	case float64:
+		return m.writeUint(0xcb, 8, math.Float64bits(v))
+	case []byte:
+		var err error
+		if utf8.Valid(v) {
+			err = m.header(len(v), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		} else {
+			err = m.header(len(v), 0, -1, 0xc4, 0xc5, 0xc6)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = m.w.Write(v)
+		return err
+	}
+
+	return fmt.Errorf("unexpected value of type %T at offset %d", t.Value, t.Offset)
+}
+
+// transcodeReader reads the big-endian binary values of MessagePack and CBOR streams
+type transcodeReader struct {
+	r io.Reader
+}
+
+func (t transcodeReader) readFull(n int) ([]byte, error) {
+	b, err := readFullBounded(t.r, n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+func (t transcodeReader) readUint(size int) (uint64, error) {
+	b, err := t.readFull(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// TranscodeFromMsgpack converts all the MessagePack values read from r to rencode values written to w
+func TranscodeFromMsgpack(w io.Writer, r io.Reader) error {
+	m := transcodeReader{r}
+	e := NewEncoder(w)
+	for {
+		var b [1]byte
+		_, err := io.ReadFull(r, b[:])
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = e.fromMsgpack(m, b[0])
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Encoder) fromMsgpack(m transcodeReader, code byte) error {
+	switch {
+	case code <= 0x7f:
+		return r.EncodeInt8(int8(code))
+	case code >= 0xe0:
+		return r.EncodeInt8(int8(code))
+	case code&0xf0 == 0x80:
+		return r.msgpackContainer(m, int(code&0x0f), 2)
+	case code&0xf0 == 0x90:
+		return r.msgpackContainer(m, int(code&0x0f), 1)
+	case code&0xe0 == 0xa0:
+		return r.msgpackBytes(m, int(code&0x1f))
+	}
+
+	switch code {
+	case 0xc0:
+		return r.EncodeNone()
+	case 0xc2:
+		return r.EncodeBool(false)
+	case 0xc3:
+		return r.EncodeBool(true)
+	case 0xc4, 0xc5, 0xc6, 0xd9, 0xda, 0xdb:
+		size := map[byte]int{0xc4: 1, 0xc5: 2, 0xc6: 4, 0xd9: 1, 0xda: 2, 0xdb: 4}[code]
+		n, err := m.readUint(size)
+		if err != nil {
+			return err
+		}
+		return r.msgpackBytes(m, int(n))
+	case 0xca:
+		v, err := m.readUint(4)
+		if err != nil {
+			return err
+		}
+		return r.EncodeFloat32(math.Float32frombits(uint32(v)))
+	case 0xcb:
+		v, err := m.readUint(8)
+		if err != nil {
+			return err
+		}
+		return r.EncodeFloat64(math.Float64frombits(v))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := m.readUint(1 << (code - 0xcc))
+		if err != nil {
+			return err
+		}
+		return r.encodeUnsigned(v)
+	case 0xd0:
+		v, err := m.readUint(1)
+		if err != nil {
+			return err
+		}
+		return r.EncodeInt8(int8(v))
+	case 0xd1:
+		v, err := m.readUint(2)
+		if err != nil {
+			return err
+		}
+		return r.EncodeInt16(int16(v))
+	case 0xd2:
+		v, err := m.readUint(4)
+		if err != nil {
+			return err
+		}
+		return r.EncodeInt32(int32(v))
+	case 0xd3:
+		v, err := m.readUint(8)
+		if err != nil {
+			return err
+		}
+		return r.EncodeInt64(int64(v))
+	case 0xdc, 0xdd:
+		n, err := m.readUint(2 << (code - 0xdc))
+		if err != nil {
+			return err
+		}
+		return r.msgpackContainer(m, int(n), 1)
+	case 0xde, 0xdf:
+		n, err := m.readUint(2 << (code - 0xde))
+		if err != nil {
+			return err
+		}
+		return r.msgpackContainer(m, int(n), 2)
+	case 0xc7, 0xc8, 0xc9, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return fmt.Errorf("MessagePack extension type 0x%x has no rencode representation", code)
+	}
+
+	return fmt.Errorf("invalid MessagePack type code 0x%x", code)
+}
+
+func (r *Encoder) msgpackBytes(m transcodeReader, n int) error {
+	b, err := m.readFull(n)
+	if err != nil {
+		return err
+	}
+	return r.EncodeBytes(b)
+}
+
+func (r *Encoder) msgpackContainer(m transcodeReader, n, pairs int) error {
+	var terminated bool
+	var err error
+	if pairs == 2 {
+		terminated, err = r.BeginDict(n)
+	} else {
+		terminated, err = r.BeginList(n)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < n*pairs; i++ {
+		b, err := m.readFull(1)
+		if err != nil {
+			return err
+		}
+		err = r.fromMsgpack(m, b[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	if terminated {
+		return r.EndContainer()
+	}
+	return nil
+}
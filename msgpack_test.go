@@ -0,0 +1,124 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// transcodeSample returns a rencode stream with values of all the types that survive a round trip
+func transcodeSample(t *testing.T) []byte {
+	var long List
+	for i := 0; i < LIST_FIXED_COUNT+6; i++ {
+		long.Add(int32(i * 100000))
+	}
+	var wide Dictionary
+	for i := 0; i < DICT_FIXED_COUNT+3; i++ {
+		wide.Add(int16(i+1000), []byte{0xff, byte(i)})
+	}
+	var value big.Int
+	value.SetUint64(^uint64(0))
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(NewList(int8(1), int8(-100), "ab", nil, true, false, float32(0.5), 1.25),
+		long, wide, int64(-1)<<40, value, []byte(strings.Repeat("x", 300)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestMsgpackEncoding(t *testing.T) {
+	t.Parallel()
+
+	var r bytes.Buffer
+	e := NewEncoder(&r)
+	err := e.Encode(NewList(int8(1), int8(-100), "a", nil, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m bytes.Buffer
+	err = TranscodeToMsgpack(&m, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0x95, 0x01, 0xd0, 0x9c, 0xa1, 'a', 0xc0, 0xc3}
+	if !bytes.Equal(m.Bytes(), expected) {
+		t.Errorf("expected % x but got % x", expected, m.Bytes())
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	input := transcodeSample(t)
+
+	var m bytes.Buffer
+	err := TranscodeToMsgpack(&m, bytes.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r bytes.Buffer
+	err = TranscodeFromMsgpack(&r, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(input, r.Bytes()) {
+		t.Errorf("transcoding mismatch:\n% x\n% x", input, r.Bytes())
+	}
+}
+
+func TestMsgpackUnsupported(t *testing.T) {
+	t.Parallel()
+
+	var value big.Int
+	value.SetUint64(^uint64(0))
+	value.Mul(&value, big.NewInt(2))
+
+	var r bytes.Buffer
+	e := NewEncoder(&r)
+	err := e.Encode(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m bytes.Buffer
+	err = TranscodeToMsgpack(&m, &r)
+	if err == nil {
+		t.Error("expected failure for integer larger than 64 bits")
+	}
+
+	for _, input := range [][]byte{
+		{0xd4, 0x01, 0x00},
+		{0xc1},
+		{0xa5, 'a'},
+		{0x92, 0x01},
+	} {
+		err := TranscodeFromMsgpack(&r, bytes.NewReader(input))
+		if err == nil {
+			t.Errorf("expected failure for % x", input)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+A patch is a List of operations, each one a Dictionary with the following keys:
+
+	op      "add", "remove" or "replace"
+	path    the value to change, with the syntax of ParsePath and without wildcards
+	value   the new value, for "add" and "replace"
+	key     optional, the dictionary key addressed by the last step of the path, matched with its type
+
+Paths do not preserve the type of keys, e.g. "[5]" addresses a dictionary key of any integer type;
+MakePatch sets "key" for the dictionary keys it adds, so that they are added with their type.
+
+Operations are applied in order; "add" inserts a list element before the specified index (or
+appends it if the index is the length of the list) and sets a dictionary key, "remove" deletes a list
+element or dictionary key and "replace" changes an existing value. Tuples are addressed like lists,
+while sets are always replaced as a whole since their elements have no path. Patches can be encoded
+like any other List.
+*/
+
+// Patch operation names
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+)
+
+// MakePatch returns the patch which turns the value 'from' into the value 'to'
+func MakePatch(from, to interface{}) List {
+	var patch List
+	for _, c := range Diff(from, to) {
+		var op Dictionary
+		switch c.Kind {
+		case Added:
+			op.Add("op", PatchAdd)
+		case Removed:
+			op.Add("op", PatchRemove)
+		case Modified:
+			op.Add("op", PatchReplace)
+		}
+		op.Add("path", c.Path)
+		if c.Kind != Removed {
+			op.Add("value", c.New)
+		}
+		if c.Kind == Added && c.Key != nil {
+			op.Add("key", c.Key)
+		}
+		patch.Add(op)
+	}
+	return patch
+}
+
+// ApplyPatch returns a copy of the value v with all the operations of the patch applied;
+// v itself is not modified.
+func ApplyPatch(v interface{}, patch List) (interface{}, error) {
+	v = cloneValue(v)
+	for i, o := range patch.Values() {
+		op, ok := o.(Dictionary)
+		if !ok {
+			return nil, fmt.Errorf("patch operation %d: expected dictionary but got %T", i, o)
+		}
+
+		var err error
+		v, err = applyOperation(v, &op)
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d: %v", i, err)
+		}
+	}
+	return v, nil
+}
+
+func applyOperation(v interface{}, op *Dictionary) (interface{}, error) {
+	name, err := op.GetString("op")
+	if err != nil {
+		return nil, fmt.Errorf("op: %v", err)
+	}
+	expr, err := op.GetString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path: %v", err)
+	}
+	path, err := ParsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range path.steps {
+		if step.wildcard {
+			return nil, fmt.Errorf("path %q: wildcards are not allowed", expr)
+		}
+	}
+	if key, ok := op.Get("key"); ok {
+		if len(path.steps) == 0 {
+			return nil, fmt.Errorf("path %q: key specified without a key step", expr)
+		}
+		path.steps[len(path.steps)-1] = pathStep{key: key, exact: true}
+	}
+
+	value, hasValue := op.Get("value")
+	switch name {
+	case PatchAdd, PatchReplace:
+		if !hasValue {
+			return nil, errors.New("missing value")
+		}
+	case PatchRemove:
+	default:
+		return nil, fmt.Errorf("invalid operation %q", name)
+	}
+
+	v, err = applyStep(v, path.steps, name, value)
+	if err != nil {
+		return nil, fmt.Errorf("path %q: %v", expr, err)
+	}
+	return v, nil
+}
+
+// applyStep applies the operation to the value addressed by the steps within v and returns the modified v
+func applyStep(v interface{}, steps []pathStep, op string, value interface{}) (interface{}, error) {
+	if len(steps) == 0 {
+		if op == PatchRemove {
+			return nil, errors.New("cannot remove the root value")
+		}
+		return value, nil
+	}
+
+	step := steps[0]
+	switch x := v.(type) {
+	case List:
+		i, ok := pathInteger(step.key)
+		if !ok {
+			return nil, fmt.Errorf("invalid list index %s", FormatText(step.key))
+		}
+		if i < 0 || i > int64(x.Length()) {
+			return nil, ErrIndexOutOfRange
+		}
+		index := int(i)
+
+		if len(steps) == 1 {
+			var err error
+			switch op {
+			case PatchAdd:
+				err = x.Insert(index, value)
+			case PatchRemove:
+				_, err = x.Remove(index)
+			case PatchReplace:
+				err = x.Set(index, value)
+			}
+			return x, err
+		}
+
+		child, err := x.Get(index)
+		if err != nil {
+			return nil, err
+		}
+		child, err = applyStep(child, steps[1:], op, value)
+		if err != nil {
+			return nil, err
+		}
+		return x, x.Set(index, child)
+	case Dictionary:
+		i := -1
+		for j, k := range x.keys {
+			if step.matchesKey(k) {
+				i = j
+				break
+			}
+		}
+
+		if len(steps) == 1 {
+			switch {
+			case op == PatchAdd && i < 0:
+				x.Add(step.key, value)
+			case i < 0:
+				return nil, ErrKeyNotFound
+			case op == PatchRemove:
+				x.deleteAt(i)
+			default:
+				x.values[i] = value
+			}
+			return x, nil
+		}
+
+		if i < 0 {
+			return nil, ErrKeyNotFound
+		}
+		child, err := applyStep(x.values[i], steps[1:], op, value)
+		if err != nil {
+			return nil, err
+		}
+		x.values[i] = child
+		return x, nil
+	case Tuple:
+		l, err := applyStep(x.List, steps, op, value)
+		if err != nil {
+			return nil, err
+		}
+		return Tuple{l.(List)}, nil
+	}
+
+	return nil, fmt.Errorf("cannot address %s within value of type %T", FormatText(step.key), v)
+}
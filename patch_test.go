@@ -0,0 +1,252 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestPatchRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	old, err := ParseText(`{'torrents': {'abc': {'name': 'a', 'peers': [1, 2, 3, 4]}, 'x.y': 1}, 'gone': None, 5: [[]]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := ParseText(`{'torrents': {'abc': {'name': 'b', 'peers': [1, 5]}, 'x.y': [1]}, 'new': {}, 5: [[1, 2]]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := FormatText(old)
+
+	patch := MakePatch(old, new)
+
+	// patches are sent over the wire like any other value
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err = e.Encode(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded List
+	err = NewDecoder(&b).Scan(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := ApplyPatch(old, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(patched, new, EqualOptions{IgnoreBytesString: true}) {
+		t.Errorf("expected %s but got %s", FormatText(new), FormatText(patched))
+	}
+	if FormatText(old) != before {
+		t.Errorf("original value modified: %s", FormatText(old))
+	}
+
+	empty := MakePatch(new, new)
+	if empty.Length() != 0 {
+		t.Error("expected empty patch for equal values")
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	v, err := ParseText(`{'list': [1, 3], 'dict': {}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := ParseText(`[
+		{'op': 'add', 'path': 'list[1]', 'value': 2},
+		{'op': 'add', 'path': 'list[3]', 'value': 4},
+		{'op': 'add', 'path': 'dict.a', 'value': None},
+		{'op': 'replace', 'path': 'dict.a', 'value': 'x'},
+		{'op': 'remove', 'path': 'list[0]'},
+	]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := ApplyPatch(v, patch.(List))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(patched) != "{b'list': [2, 3, 4], b'dict': {b'a': b'x'}}" {
+		t.Errorf("unexpected patched value %s", FormatText(patched))
+	}
+
+	for _, invalid := range []string{
+		`[{'op': 'remove', 'path': 'missing'}]`,
+		`[{'op': 'replace', 'path': 'list[5]', 'value': 1}]`,
+		`[{'op': 'add', 'path': 'list[*]', 'value': 1}]`,
+		`[{'op': 'add', 'path': 'list[0]'}]`,
+		`[{'op': 'move', 'path': 'list[0]'}]`,
+		`[{'op': 'remove', 'path': ''}]`,
+		`[{'op': 'add', 'path': 'list[0].a', 'value': 1}]`,
+		`[1]`,
+	} {
+		patch, err := ParseText(invalid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ApplyPatch(v, patch.(List))
+		if err == nil {
+			t.Errorf("expected failure for %s", invalid)
+		}
+	}
+}
+
+func TestPatchTypedKeys(t *testing.T) {
+	t.Parallel()
+
+	var huge big.Int
+	huge.SetString("123456789012345678901234567890", 10)
+
+	var old, new Dictionary
+	old.Add(float32(0.1), int8(1))
+	old.Add(huge, List{})
+	old.Add(int64(5), int8(1))
+	old.Add(uint16(7), int8(1))
+	new.Add(float32(0.1), int8(2))
+	new.Add(huge, NewList(int8(1)))
+	new.Add(int16(5), int8(1))
+	new.Add(uint16(7), int8(2))
+	new.Add(float32(2.5), int8(3))
+
+	var b big.Int
+	b.SetString("-98765432109876543210987654321", 10)
+	new.Add(b, nil)
+
+	patch := MakePatch(old, new)
+	patched, err := ApplyPatch(old, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(patched, new, EqualOptions{}) {
+		t.Errorf("expected %s but got %s", FormatText(new), FormatText(patched))
+	}
+
+	// keys are preserved with their type also over the wire, except for integer widths
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	err = e.Encode(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded List
+	err = NewDecoder(&buf).Scan(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err = ApplyPatch(old, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(patched, new, EqualOptions{IgnoreNumericWidth: true}) {
+		t.Errorf("expected %s but got %s", FormatText(new), FormatText(patched))
+	}
+	d := patched.(Dictionary)
+	if _, ok := d.Get(float32(2.5)); !ok {
+		t.Errorf("float32 key not added: %s", FormatText(patched))
+	}
+}
+
+func TestPatchContainerAndBigKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, testCase := range []struct {
+		old, new string
+	}{
+		{`{(1, 2): 'x'}`, `{(1, 2): 'x'}`},
+		{`{(1, 2): 'x', [3]: 'y'}`, `{(1, 2): 'z', [3]: 'y'}`},
+		{`{(1, 2): 'x', 'a': 1}`, `{'a': 1}`},
+		{`{18446744073709551616: 1, b'a': 2}`, `{18446744073709551616: 1, b'a': 2}`},
+		{`{18446744073709551616: 1, b'a': 2}`, `{b'a': 2}`},
+		{`{18446744073709551616: 1, b'a': 2}`, `{18446744073709551616: 3, b'a': 2}`},
+	} {
+		old, err := ParseTypedText(testCase.old)
+		if err != nil {
+			t.Fatal(err)
+		}
+		new, err := ParseTypedText(testCase.new)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := old.(Dictionary)
+
+		if patch := MakePatch(d, d.Clone()); patch.Length() != 0 {
+			t.Errorf("%s: expected empty patch for a clone but got %s", testCase.old, FormatText(patch))
+		}
+
+		patched, err := ApplyPatch(old, MakePatch(old, new))
+		if err != nil {
+			t.Errorf("%s -> %s: %v", testCase.old, testCase.new, err)
+			continue
+		}
+		if !Equal(patched, new, EqualOptions{}) {
+			t.Errorf("expected %s but got %s", testCase.new, FormatText(patched))
+		}
+	}
+}
+
+func TestPatchTuplesAndSets(t *testing.T) {
+	t.Parallel()
+
+	old, err := ParseTypedText(`{'t': (1, (2, 3)), 's': {1, 2}, 'r': (1, 2, 3)}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := ParseTypedText(`{'t': (1, (2, 4), 5), 's': {2, 3}, 'r': (1,)}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := FormatText(old)
+
+	patched, err := ApplyPatch(old, MakePatch(old, new))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(patched, new, EqualOptions{}) {
+		t.Errorf("expected %s but got %s", FormatText(new), FormatText(patched))
+	}
+	if FormatText(old) != before {
+		t.Errorf("original value modified: %s", FormatText(old))
+	}
+
+	matches, err := Lookup(new, "t[1][1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(NewList(matches...)) != "[4]" {
+		t.Errorf("unexpected tuple lookup %s", FormatText(NewList(matches...)))
+	}
+
+	patch, err := ParseText(`[{'op': 'add', 'path': 's[0]', 'value': 1}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ApplyPatch(old, patch.(List)); err == nil {
+		t.Error("expected failure for set element path")
+	}
+}
@@ -0,0 +1,341 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/*
+Paths select values nested in lists, tuples and dictionaries; a path is a sequence of steps:
+
+	name         dictionary key, compared like Dictionary.Get; the first step has no leading dot
+	.name        dictionary key
+	.*  [*]      all the values of a dictionary or list
+	[0]          list element, or dictionary key of any integer type with the same value
+	[2.5]        dictionary key of float64 type, or of float32 type written with the same digits
+	['a.b']      dictionary key written as a Python literal, as accepted by ParseTypedText
+	[(1, 2)]     dictionary key of tuple type, or of list type for [[1, 2]], compared regardless of integer widths
+
+For example "torrents.*.peers[0].ip" selects the address of the first peer of every torrent.
+The empty path selects the value itself; a key step selects the values of all the entries with a matching key.
+*/
+
+// pathStep is a single step of a Path; a nil key with wildcard false matches the None key
+type pathStep struct {
+	key      interface{}
+	wildcard bool
+	// exact is set to match only keys of the same type, as for a patch "key"
+	exact bool
+}
+
+// Path is a compiled path expression, see ParsePath
+type Path struct {
+	expr  string
+	steps []pathStep
+}
+
+// ParsePath compiles a path expression
+func ParsePath(expr string) (Path, error) {
+	p := Path{expr: expr}
+	i := 0
+	for i < len(expr) {
+		switch {
+		case expr[i] == '[':
+			if strings.HasPrefix(expr[i:], "[*]") {
+				p.steps = append(p.steps, pathStep{wildcard: true})
+				i += len("[*]")
+				continue
+			}
+
+			tp := textParser{s: expr, pos: i + 1, typed: true}
+			key, err := tp.value()
+			if err == nil {
+				err = tp.expect(']')
+			}
+			if err != nil {
+				return p, fmt.Errorf("path %q: %v", expr, err)
+			}
+			p.steps = append(p.steps, pathStep{key: key})
+			i = tp.pos
+		case expr[i] == '.' || i == 0:
+			if expr[i] == '.' {
+				i++
+			}
+			end := i
+			for end < len(expr) && expr[end] != '.' && expr[end] != '[' {
+				end++
+			}
+			name := expr[i:end]
+			if name == "" {
+				return p, fmt.Errorf("path %q: empty key at offset %d", expr, i)
+			}
+			if name == "*" {
+				p.steps = append(p.steps, pathStep{wildcard: true})
+			} else {
+				p.steps = append(p.steps, pathStep{key: []byte(name)})
+			}
+			i = end
+		default:
+			return p, fmt.Errorf("path %q: unexpected %q at offset %d", expr, expr[i], i)
+		}
+	}
+	return p, nil
+}
+
+// String returns the expression the path has been compiled from
+func (p Path) String() string {
+	return p.expr
+}
+
+// pathInteger returns the value of an integer key
+func pathInteger(v interface{}) (int64, bool) {
+	switch x := v.(type) {
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	case int:
+		return int64(x), true
+	case big.Int:
+		if x.IsInt64() {
+			return x.Int64(), true
+		}
+	}
+	return 0, false
+}
+
+// sameInteger returns true if both values are integers with the same value, regardless of their types
+func sameInteger(a, b interface{}) bool {
+	if i, ok := pathInteger(a); ok {
+		if j, ok := pathInteger(b); ok {
+			return i == j
+		}
+	}
+	x, ok := bigValue(a)
+	if !ok {
+		return false
+	}
+	y, ok := bigValue(b)
+	return ok && x.Cmp(y) == 0
+}
+
+// sameKey returns true if the dictionary key k is the specified key, with the same type
+func sameKey(k, key interface{}) bool {
+	return keyMatches(k, normalizeKey(key))
+}
+
+// matchesKey returns true if the step selects the dictionary entry with the specified key
+func (s pathStep) matchesKey(k interface{}) bool {
+	if s.wildcard {
+		return true
+	}
+	if s.exact {
+		return sameKey(k, s.key)
+	}
+	if _, ok := bigValue(s.key); ok {
+		return sameInteger(s.key, k)
+	}
+	if f, ok := s.key.(float64); ok {
+		if k, ok := k.(float32); ok {
+			// a float32 key is selected by the shortest decimal representation of its value
+			return float64(k) == f || strconv.FormatFloat(float64(k), 'g', -1, 32) == strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+	switch s.key.(type) {
+	case List, Tuple, Set, Dictionary:
+		return Equal(k, s.key, EqualOptions{IgnoreNumericWidth: true, IgnoreBytesString: true})
+	}
+	return sameKey(k, s.key)
+}
+
+// matchesIndex returns true if the step selects the list element at the specified index
+func (s pathStep) matchesIndex(i int) bool {
+	if s.wildcard {
+		return true
+	}
+	j, ok := pathInteger(s.key)
+	return ok && j == int64(i)
+}
+
+// Lookup returns all the values selected by the path within v, in traversal order
+func (p Path) Lookup(v interface{}) []interface{} {
+	return lookupSteps(nil, v, p.steps)
+}
+
+func lookupSteps(matches []interface{}, v interface{}, steps []pathStep) []interface{} {
+	if len(steps) == 0 {
+		return append(matches, v)
+	}
+
+	step := steps[0]
+	switch x := v.(type) {
+	case List, Tuple:
+		l, _ := asList(x)
+		for i, e := range l.values {
+			if step.matchesIndex(i) {
+				matches = lookupSteps(matches, e, steps[1:])
+			}
+		}
+	case Dictionary:
+		for i, k := range x.keys {
+			if step.matchesKey(k) {
+				matches = lookupSteps(matches, x.values[i], steps[1:])
+			}
+		}
+	}
+	return matches
+}
+
+// Lookup returns all the values selected by the path expression within v, e.g.
+//
+//	rencode.Lookup(status, "torrents.*.peers[0].ip")
+//
+// See ParsePath for the syntax of expressions.
+func Lookup(v interface{}, expr string) ([]interface{}, error) {
+	p, err := ParsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Lookup(v), nil
+}
+
+// DecodePath reads the next value of the stream and returns the sub-values selected by the path;
+// lists and dictionaries which cannot contain a match are skipped without building them, although
+// their strings and numbers are still read.
+// If no more objects are available, an io.EOF error will be returned.
+func (r *Decoder) DecodePath(p Path) ([]interface{}, error) {
+	t, err := r.NextToken()
+	if err != nil {
+		return nil, err
+	}
+	return r.decodePath(nil, t, p.steps)
+}
+
+func (r *Decoder) decodePath(matches []interface{}, t Token, steps []pathStep) ([]interface{}, error) {
+	if t.Kind == EndToken {
+		return matches, fmt.Errorf("unexpected terminator at offset %d", t.Offset)
+	}
+	if len(steps) == 0 {
+		v, err := r.decodeToken(t)
+		if err != nil {
+			return matches, err
+		}
+		return append(matches, v), nil
+	}
+	if t.Kind == ValueToken {
+		return matches, nil
+	}
+
+	step := steps[0]
+	for i := 0; t.Length < 0 || i < t.Length; i++ {
+		c, err := nextInnerToken(r)
+		if err != nil {
+			return matches, err
+		}
+		if c.Kind == EndToken && t.Length < 0 {
+			return matches, nil
+		}
+
+		match := step.matchesIndex(i)
+		if t.Kind == DictToken {
+			key, err := r.decodeToken(c)
+			if err != nil {
+				return matches, err
+			}
+			match = step.matchesKey(key)
+
+			c, err = nextInnerToken(r)
+			if err != nil {
+				return matches, err
+			}
+			if c.Kind == EndToken && t.Length < 0 {
+				// like DecodeNext, a key without value right before the terminator has value None
+				if match && len(steps) == 1 {
+					matches = append(matches, nil)
+				}
+				return matches, nil
+			}
+		}
+
+		if match {
+			matches, err = r.decodePath(matches, c, steps[1:])
+		} else {
+			err = r.skipToken(c)
+		}
+		if err != nil {
+			return matches, err
+		}
+	}
+	return matches, nil
+}
+
+// decodeToken returns the complete value started by the token
+func (r *Decoder) decodeToken(t Token) (interface{}, error) {
+	switch t.Kind {
+	case ValueToken:
+		return t.Value, nil
+	case EndToken:
+		return nil, fmt.Errorf("unexpected terminator at offset %d", t.Offset)
+	}
+
+	v, err := r.decode(t.Code)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return v, err
+}
+
+// skipToken reads all the tokens of the value started by the token
+func (r *Decoder) skipToken(t Token) error {
+	switch t.Kind {
+	case ValueToken:
+		return nil
+	case EndToken:
+		return fmt.Errorf("unexpected terminator at offset %d", t.Offset)
+	}
+
+	n := t.Length
+	if t.Kind == DictToken {
+		n *= 2
+	}
+	for i := 0; t.Length < 0 || i < n; i++ {
+		c, err := nextInnerToken(r)
+		if err != nil {
+			return err
+		}
+		if c.Kind == EndToken && t.Length < 0 {
+			return nil
+		}
+		err = r.skipToken(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func pathSample(t *testing.T) interface{} {
+	v, err := ParseText(`{
+		'torrents': {
+			'abc': {'name': 'a.iso', 'peers': [{'ip': '10.0.0.1'}, {'ip': '10.0.0.2'}]},
+			'def': {'name': 'b.iso', 'peers': []},
+			'ghi': {'name': 'c.iso', 'peers': [{'ip': '10.0.0.3'}]},
+		},
+		'a.b': True,
+		1: 'one',
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	v := pathSample(t)
+	for _, testCase := range []struct {
+		expr     string
+		expected string
+	}{
+		{"torrents.*.peers[0].ip", "[b'10.0.0.1', b'10.0.0.3']"},
+		{"torrents.abc.peers[*].ip", "[b'10.0.0.1', b'10.0.0.2']"},
+		{"torrents['def'].name", "[b'b.iso']"},
+		{"['a.b']", "[True]"},
+		{"[1]", "[b'one']"},
+		{"torrents.xyz.name", "[]"},
+		{"torrents.abc.name.length", "[]"},
+		{"", "[" + FormatText(v) + "]"},
+	} {
+		matches, err := Lookup(v, testCase.expr)
+		if err != nil {
+			t.Errorf("%q: %v", testCase.expr, err)
+			continue
+		}
+		if FormatText(NewList(matches...)) != testCase.expected {
+			t.Errorf("%q: expected %s but got %s", testCase.expr, testCase.expected, FormatText(NewList(matches...)))
+		}
+
+		// the streaming variant must select the same values
+		var b bytes.Buffer
+		e := NewEncoder(&b)
+		err = e.Encode(v, int8(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _ := ParsePath(testCase.expr)
+		d := NewDecoder(&b)
+		streamed, err := d.DecodePath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if FormatText(NewList(streamed...)) != testCase.expected {
+			t.Errorf("%q: expected %s but streaming got %s", testCase.expr, testCase.expected, FormatText(NewList(streamed...)))
+		}
+		next, err := d.DecodeNext()
+		if err != nil || next != int8(1) {
+			t.Errorf("%q: value after the path query not found: %v (%v)", testCase.expr, next, err)
+		}
+	}
+}
+
+func TestParsePathInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"a..b", "a.", "a[0", "a['b'", "a[0]b", "[]"} {
+		_, err := ParsePath(expr)
+		if err == nil {
+			t.Errorf("expected failure for %q", expr)
+		}
+	}
+}
+
+func TestDecodePathTruncated(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(pathSample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, _ := ParsePath("torrents.*.name")
+	d := NewDecoder(strings.NewReader(b.String()[:b.Len()-5]))
+	_, err = d.DecodePath(p)
+	if err == nil {
+		t.Error("expected failure for truncated input")
+	}
+}
+
+func TestLookupDuplicateAndFloatKeys(t *testing.T) {
+	t.Parallel()
+
+	var d Dictionary
+	d.Add("a", int8(1))
+	d.Add(float32(0.1), []byte("f32"))
+	d.Add(2.5, []byte("f64"))
+	d.Add("a", int8(2))
+	d.Add(float32(2.5), []byte("f32"))
+
+	for _, testCase := range []struct {
+		expr     string
+		expected string
+	}{
+		{"a", "[1, 2]"},
+		{"[0.1]", "[b'f32']"},
+		{"[2.5]", "[b'f64', b'f32']"},
+		{"[0.10000000001]", "[]"},
+	} {
+		matches, err := Lookup(d, testCase.expr)
+		if err != nil {
+			t.Errorf("%q: %v", testCase.expr, err)
+			continue
+		}
+		if FormatText(NewList(matches...)) != testCase.expected {
+			t.Errorf("%q: expected %s but got %s", testCase.expr, testCase.expected, FormatText(NewList(matches...)))
+		}
+
+		var b bytes.Buffer
+		e := NewEncoder(&b)
+		err = e.Encode(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _ := ParsePath(testCase.expr)
+		streamed, err := NewDecoder(&b).DecodePath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if FormatText(NewList(streamed...)) != testCase.expected {
+			t.Errorf("%q: expected %s but streaming got %s", testCase.expr, testCase.expected, FormatText(NewList(streamed...)))
+		}
+	}
+}
+
+func TestDecodePathKeyWithoutValue(t *testing.T) {
+	t.Parallel()
+
+	input := []byte{CHR_DICT, STR_FIXED_START + 1, 'a', 1, STR_FIXED_START + 1, 'b', CHR_TERM, 2}
+	for _, testCase := range []struct {
+		expr, expected string
+	}{
+		{"a", "[1]"},
+		{"b", "[None]"},
+		{"c", "[]"},
+	} {
+		p, err := ParsePath(testCase.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoder(bytes.NewReader(input))
+		matches, err := d.DecodePath(p)
+		if err != nil {
+			t.Fatalf("%q: %v", testCase.expr, err)
+		}
+		if FormatText(NewList(matches...)) != testCase.expected {
+			t.Errorf("%q: expected %s but got %s", testCase.expr, testCase.expected, FormatText(NewList(matches...)))
+		}
+		next, err := d.DecodeNext()
+		if err != nil || next != int8(2) {
+			t.Errorf("%q: value after the dictionary not found: %v (%v)", testCase.expr, next, err)
+		}
+	}
+}
+
+func TestLookupContainerKeys(t *testing.T) {
+	t.Parallel()
+
+	var d Dictionary
+	d.Add(NewTuple(int64(1), []byte("a")), int8(1))
+	d.Add(NewList(int8(1), "a"), int8(2))
+
+	for _, testCase := range []struct {
+		expr     string
+		expected string
+	}{
+		{"[(1, 'a')]", "[1]"},
+		{"[[1, 'a']]", "[2]"},
+		{"[(1, 'b')]", "[]"},
+	} {
+		matches, err := Lookup(d, testCase.expr)
+		if err != nil {
+			t.Errorf("%q: %v", testCase.expr, err)
+			continue
+		}
+		if FormatText(NewList(matches...)) != testCase.expected {
+			t.Errorf("%q: expected %s but got %s", testCase.expr, testCase.expected, FormatText(NewList(matches...)))
+		}
+	}
+}
@@ -129,6 +129,7 @@ func (r *Encoder) encodeSingle(data interface{}) error {
 		if x <= math.MaxInt8 {
 			return r.EncodeInt8(int8(x))
 		}
+		return r.EncodeInt16(int16(x))
 	case uint16:
 		if x <= math.MaxInt8 {
 			return r.EncodeInt8(int8(x))
@@ -136,6 +137,7 @@ func (r *Encoder) encodeSingle(data interface{}) error {
 		if x <= math.MaxInt16 {
 			return r.EncodeInt16(int16(x))
 		}
+		return r.EncodeInt32(int32(x))
 	case int16:
 		if math.MinInt8 <= x && x <= math.MaxInt8 {
 			return r.EncodeInt8(int8(x))
@@ -151,6 +153,7 @@ func (r *Encoder) encodeSingle(data interface{}) error {
 		if x <= math.MaxInt32 {
 			return r.EncodeInt32(int32(x))
 		}
+		return r.EncodeInt64(int64(x))
 	case int32:
 		if math.MinInt8 <= x && x <= math.MaxInt8 {
 			return r.EncodeInt8(int8(x))
@@ -177,9 +180,8 @@ func (r *Encoder) encodeSingle(data interface{}) error {
 		}
 		return r.EncodeBigNumber(s)
 	default:
-		return fmt.Errorf("could not encode data of type %T", data)
+		return r.encodeReflected(data)
 	}
-	panic("unexpected fallthrough")
 }
 func convertAssignInteger(src, dest interface{}) error {
 	switch sv := src.(type) {
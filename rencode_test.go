@@ -548,46 +548,20 @@ func dictCompareVerbose(t *testing.T, a, b *Dictionary) bool {
 		t.Errorf("dictionary length mismatch: %v != %v", a.Length(), b.Length())
 		return false
 	}
-
-	matching := true
 	for _, k := range a.Keys() {
-		// get value on both dictionaries
-		aV, ok := a.Get(k)
-		if !ok {
-			t.Errorf("value with key %v not found on first dictionary", k)
-			return false
-		}
-		bV, ok := b.Get(k)
-		if !ok {
+		if !b.Has(k) {
 			t.Errorf("value with key %v not found on second dictionary", k)
 			return false
 		}
-
-		// normalize both values to string if they are of []byte type
-		if v, ok := aV.([]byte); ok {
-			aV = string(v)
-		}
-		if v, ok := bV.([]byte); ok {
-			bV = string(v)
-		}
-
-		switch v := aV.(type) {
-		case Dictionary:
-			d2 := bV.(Dictionary)
-			if !dictCompareVerbose(t, &v, &d2) {
-				matching = false
-			}
-		case List:
-			l2 := bV.(List)
-			if !listCompareVerbose(t, &v, &l2) {
-				matching = false
-			}
-		default:
-			if aV != bV {
-				t.Fatalf("index %q: expected %v (type %T) but %v (type %T) found", k, v, v, bV, bV)
-			}
-		}
 	}
+	return compareVerbose(t, *a, *b)
+}
 
-	return matching
+// compareVerbose reports all the differences between two values; byte slices and strings are compared by content
+func compareVerbose(t *testing.T, a, b interface{}) bool {
+	changes := EqualOptions{IgnoreBytesString: true}.Diff(a, b)
+	for _, c := range changes {
+		t.Error(c)
+	}
+	return len(changes) == 0
 }
@@ -20,6 +20,7 @@
 package rencode
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -37,14 +38,37 @@ func (co ConversionOverflow) Error() string {
 // Scan will scan the decoder data to fill in the specified target objects; if possible,
 // a conversion will be performed. If targets have not pointer types or if the conversion is
 // not possible, an error will be returned.
+// Targets implementing Unmarshaler decode themselves.
 func (d *Decoder) Scan(targets ...interface{}) error {
+	return d.scan(context.Background(), targets)
+}
+
+// scan implements Scan and ScanContext; the context is checked before each target
+func (d *Decoder) scan(ctx context.Context, targets []interface{}) error {
 	for i, target := range targets {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		if u, ok := target.(Unmarshaler); ok {
+			// errors are returned as they are, to preserve their type
+			err := u.UnmarshalRencode(d)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		src, err := d.DecodeNext()
 		if err != nil {
 			return err
 		}
 
-		err = convertAssign(src, target)
+		ok, err := assignTime(src, target, d.timeFormat)
+		if !ok {
+			err = convertAssign(src, target)
+		}
 		if err != nil {
 			return fmt.Errorf("scan element %d: %v", i, err)
 		}
@@ -83,6 +107,10 @@ func (l *List) Shift(n int) int {
 }
 
 func convertAssign(src, dest interface{}) error {
+	if ok, err := assignTime(src, dest, TimeFloatSeconds); ok {
+		return err
+	}
+
 	switch src := src.(type) {
 	case bool:
 		switch dest := dest.(type) {
@@ -95,6 +123,34 @@ func convertAssign(src, dest interface{}) error {
 		case *List:
 			*dest = src
 			return nil
+		case *Tuple:
+			*dest = src.ToTuple()
+			return nil
+		case *Set:
+			set, err := src.ToSet()
+			if err != nil {
+				return err
+			}
+			*dest = set
+			return nil
+		}
+	case Tuple:
+		switch dest := dest.(type) {
+		case *Tuple:
+			*dest = src
+			return nil
+		case *List:
+			*dest = src.List
+			return nil
+		}
+	case Set:
+		switch dest := dest.(type) {
+		case *Set:
+			*dest = src
+			return nil
+		case *List:
+			*dest = src.List()
+			return nil
 		}
 	case Dictionary:
 		switch dest := dest.(type) {
@@ -140,6 +196,9 @@ func convertAssign(src, dest interface{}) error {
 		case *float32:
 			*dest = float32(src)
 			return nil
+		case *float64:
+			*dest = float64(src)
+			return nil
 		case *bool:
 			*dest = src == 1
 			return nil
@@ -149,12 +208,18 @@ func convertAssign(src, dest interface{}) error {
 		case *float32:
 			*dest = float32(src)
 			return nil
+		case *float64:
+			*dest = float64(src)
+			return nil
 		}
 	case int32:
 		switch dest := dest.(type) {
 		case *float32:
 			*dest = float32(src)
 			return nil
+		case *float64:
+			*dest = float64(src)
+			return nil
 		}
 	}
 
@@ -0,0 +1,451 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// structField is the compiled description of an exported struct field, possibly promoted from an embedded struct
+type structField struct {
+	// index is the sequence of field indexes, as used by reflect.Value.FieldByIndex
+	index []int
+	// name is the dictionary key of the field
+	name string
+	typ  reflect.Type
+	tag  fieldTag
+	// defaultValue is the parsed default value of the field, if any; it must be cloned before use
+	defaultValue interface{}
+	defaultErr   error
+}
+
+// structCodec is the compiled description of a struct type, shared by ToStruct and FromStruct
+type structCodec struct {
+	fields []structField
+	// positional is set for structs embedding Positional, which are mapped to tuples
+	positional bool
+	err        error
+}
+
+// structCodecs caches the *structCodec of each reflect.Type
+var structCodecs sync.Map
+
+// cachedStructCodec returns the codec of the specified struct type, compiling it on first use
+func cachedStructCodec(t reflect.Type) *structCodec {
+	if c, ok := structCodecs.Load(t); ok {
+		return c.(*structCodec)
+	}
+	c, _ := structCodecs.LoadOrStore(t, newStructCodec(t))
+	return c.(*structCodec)
+}
+
+// embeddedStruct is a struct whose fields are promoted to the struct being compiled
+type embeddedStruct struct {
+	typ   reflect.Type
+	index []int
+	// annotations are inherited by the promoted fields
+	annotations []string
+	// optional is set for structs embedded through a pointer, whose fields can all be missing
+	optional bool
+}
+
+// newStructCodec compiles the fields of a struct type; the fields of embedded structs are promoted
+// with the same rules of encoding/json: fields are collected breadth-first, a field hides the
+// fields with the same name at deeper levels and fields with the same name at the same level
+// hide each other, also when the same struct is embedded more than once at a level.
+// The fields of structs embedded through a pointer are optional.
+func newStructCodec(t reflect.Type) *structCodec {
+	var c structCodec
+	var remain []int
+	// names holds the names found at shallower levels
+	names := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+
+	current := []embeddedStruct{{typ: t}}
+	// counts holds how many times each struct of the current level is embedded
+	counts := map[reflect.Type]int{t: 1}
+	for len(current) != 0 {
+		var next []embeddedStruct
+		nextCounts := map[reflect.Type]int{}
+		var level []structField
+		count := map[string]int{}
+
+		for _, es := range current {
+			if visited[es.typ] {
+				continue
+			}
+			visited[es.typ] = true
+
+			l := es.typ.NumField()
+			for i := 0; i < l; i++ {
+				f := es.typ.Field(i)
+				if f.Anonymous && f.Type == positionalType {
+					c.positional = c.positional || len(es.index) == 0
+					continue
+				}
+				ft := parseFieldTag(f)
+				if ft.err != nil && c.err == nil {
+					c.err = ft.err
+				}
+				ft.annotations = append(append([]string(nil), es.annotations...), ft.annotations...)
+				ft.optional = ft.optional || es.optional
+				index := append(append([]int(nil), es.index...), i)
+
+				et := f.Type
+				if et.Kind() == reflect.Ptr {
+					et = et.Elem()
+				}
+				if (f.Anonymous && !ft.noinline) || ft.inline {
+					if et.Kind() == reflect.Struct {
+						// embedded pointers to unexported structs cannot be allocated
+						if f.PkgPath != "" && f.Type.Kind() == reflect.Ptr {
+							continue
+						}
+						nextCounts[et]++
+						if nextCounts[et] == 1 {
+							next = append(next, embeddedStruct{et, index, ft.annotations, es.optional || f.Type.Kind() == reflect.Ptr})
+						}
+						continue
+					}
+					if ft.inline && c.err == nil {
+						c.err = fmt.Errorf("field %q: only struct fields can be inlined", f.Name)
+					}
+				}
+				if f.PkgPath != "" {
+					// unexported field
+					continue
+				}
+
+				sf := structField{
+					index: index,
+					name:  ToSnakeCase(f.Name),
+					typ:   f.Type,
+					tag:   ft,
+				}
+				if ft.remain {
+					remain = append(remain, index...)
+					if len(remain) != len(index) && c.err == nil {
+						c.err = fmt.Errorf("field %q: only one field can collect the remaining keys", f.Name)
+					}
+					c.fields = append(c.fields, sf)
+					continue
+				}
+				if ft.hasDefault {
+					sf.defaultValue, sf.defaultErr = ParseText(ft.defaultText)
+					if sf.defaultErr != nil {
+						sf.defaultErr = fmt.Errorf("field %q: default value: %v", f.Name, sf.defaultErr)
+					}
+				}
+				if !names[sf.name] {
+					level = append(level, sf)
+					count[sf.name] += counts[es.typ]
+				}
+			}
+		}
+
+		for _, sf := range level {
+			if count[sf.name] == 1 {
+				c.fields = append(c.fields, sf)
+			}
+			names[sf.name] = true
+		}
+		current, counts = next, nextCounts
+	}
+
+	// keep the order of declaration, with promoted fields in place of the embedded struct
+	sort.Slice(c.fields, func(i, j int) bool {
+		a, b := c.fields[i].index, c.fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return &c
+}
+
+// timeFormat returns the time format of the field, or the specified default
+func (f *structField) timeFormat(def TimeFormat) TimeFormat {
+	if f.tag.hasTimeFormat {
+		return f.tag.timeFormat
+	}
+	return def
+}
+
+// fieldByIndex returns the nested field with the specified index; embedded nil pointers are allocated
+// if alloc is set, otherwise an invalid value is returned
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// FromStruct maps a struct, or a pointer to a struct, into a Dictionary recursively; it is the reverse of ToStruct.
+// Fields with the specified annotation are excluded and the entries of a field tagged as remain are added
+// after all the other fields. Nested structs and maps are converted to dictionaries (with sorted keys),
+// structs embedding Positional, slices and arrays to lists and nil pointers to None.
+func FromStruct(src interface{}, excludeAnnotationTag string) (Dictionary, error) {
+	return FromStructWithOptions(src, StructOptions{ExcludeAnnotationTag: excludeAnnotationTag})
+}
+
+// FromStructWithOptions is like FromStruct, with the exclusion annotation and the default time format
+// specified by the options; the other options are only used by ToStructWithOptions.
+func FromStructWithOptions(src interface{}, opts StructOptions) (Dictionary, error) {
+	v := reflect.Indirect(reflect.ValueOf(src))
+	if v.Kind() != reflect.Struct {
+		return Dictionary{}, fmt.Errorf("expected struct, got %T", src)
+	}
+	return structToDictionary(v, opts)
+}
+
+// FromStructTuple maps a struct, or a pointer to a struct, into a Tuple with the values of its fields
+// in order of declaration, as done by FromStruct for structs embedding Positional; the entries of
+// a field tagged as remain are added last.
+func FromStructTuple(src interface{}, opts StructOptions) (Tuple, error) {
+	v := reflect.Indirect(reflect.ValueOf(src))
+	if v.Kind() != reflect.Struct {
+		return Tuple{}, fmt.Errorf("expected struct, got %T", src)
+	}
+	return structToTuple(v, opts)
+}
+
+// ToValue converts a Go value to the value written by Encode, with the default time format specified
+// by the options: maps are converted to dictionaries with sorted keys, so that the same value is always
+// encoded the same way, while structs, slices and arrays are converted like in FromStruct.
+func ToValue(v interface{}, opts StructOptions) (interface{}, error) {
+	return fromValue(reflect.ValueOf(v), opts)
+}
+
+func structToTuple(v reflect.Value, opts StructOptions) (Tuple, error) {
+	var t Tuple
+	c := cachedStructCodec(v.Type())
+	if c.err != nil {
+		return t, c.err
+	}
+
+	var rest interface{}
+	for _, f := range c.fields {
+		if opts.ExcludeAnnotationTag != "" && f.tag.hasAnnotation(opts.ExcludeAnnotationTag) {
+			continue
+		}
+		fv := fieldByIndex(v, f.index, false)
+		if !fv.IsValid() {
+			// within a nil embedded pointer
+			t.Add(nil)
+			continue
+		}
+		fopts := opts
+		fopts.TimeFormat = f.timeFormat(opts.TimeFormat)
+		value, err := fromValue(fv, fopts)
+		if err != nil {
+			return t, fmt.Errorf("field %q: %v", f.name, err)
+		}
+		if f.tag.remain {
+			rest = value
+			continue
+		}
+		t.Add(value)
+	}
+
+	if rest != nil {
+		remaining, ok := rest.(List)
+		if !ok {
+			return t, fmt.Errorf("remaining elements must be a list, got %T", rest)
+		}
+		t.Add(remaining.values...)
+	}
+	return t, nil
+}
+
+func structToDictionary(v reflect.Value, opts StructOptions) (Dictionary, error) {
+	var d Dictionary
+	c := cachedStructCodec(v.Type())
+	if c.err != nil {
+		return d, c.err
+	}
+
+	var rest interface{}
+	for _, f := range c.fields {
+		if opts.ExcludeAnnotationTag != "" && f.tag.hasAnnotation(opts.ExcludeAnnotationTag) {
+			continue
+		}
+		fv := fieldByIndex(v, f.index, false)
+		if !fv.IsValid() {
+			// within a nil embedded pointer
+			continue
+		}
+		fopts := opts
+		fopts.TimeFormat = f.timeFormat(opts.TimeFormat)
+		value, err := fromValue(fv, fopts)
+		if err != nil {
+			return d, fmt.Errorf("field %q: %v", f.name, err)
+		}
+		if f.tag.remain {
+			rest = value
+			continue
+		}
+		d.Add(f.name, value)
+	}
+
+	if rest != nil {
+		remaining, ok := rest.(Dictionary)
+		if !ok {
+			return d, fmt.Errorf("remaining keys must be a dictionary, got %T", rest)
+		}
+		for i, k := range remaining.keys {
+			if d.Has(k) {
+				return d, fmt.Errorf("remaining key %s: %v", FormatText(k), ErrKeyAlreadyExists)
+			}
+			d.Add(k, remaining.values[i])
+		}
+	}
+	return d, nil
+}
+
+// fromValue converts a Go value to the equivalent value of one of the types supported by Encode
+func fromValue(v reflect.Value, opts StructOptions) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Type() {
+	case timeType, durationType:
+		t, _ := timeValue(v.Interface(), opts.TimeFormat)
+		return t, nil
+	case listType, dictionaryType, bigIntType, bytesType:
+		return v.Interface(), nil
+	case tupleType:
+		return v.Interface().(Tuple).List, nil
+	case setType:
+		s := v.Interface().(Set)
+		return s.List(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int:
+		return int(v.Int()), nil
+	case reflect.Int8:
+		return int8(v.Int()), nil
+	case reflect.Int16:
+		return int16(v.Int()), nil
+	case reflect.Int32:
+		return int32(v.Int()), nil
+	case reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		// unsigned values are widened, as they may not fit the signed type of the same size
+		return int64(v.Uint()), nil
+	case reflect.Uint, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32:
+		return float32(v.Float()), nil
+	case reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return fromValue(v.Elem(), opts)
+	case reflect.Struct:
+		if cachedStructCodec(v.Type()).positional {
+			t, err := structToTuple(v, opts)
+			return t.List, err
+		}
+		return structToDictionary(v, opts)
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessMapKey(keys[i], keys[j])
+		})
+		var d Dictionary
+		for _, k := range keys {
+			key, err := fromValue(k, opts)
+			if err != nil {
+				return nil, err
+			}
+			value, err := fromValue(v.MapIndex(k), opts)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %v", FormatText(key), err)
+			}
+			d.Add(key, value)
+		}
+		return d, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+		l := List{make([]interface{}, v.Len())}
+		for i := range l.values {
+			e, err := fromValue(v.Index(i), opts)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %v", i, err)
+			}
+			l.values[i] = e
+		}
+		return l, nil
+	}
+	return nil, fmt.Errorf("could not encode data of type %v", v.Type())
+}
+
+// lessMapKey orders map keys so that dictionaries converted from maps are deterministic
+func lessMapKey(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// encodeReflected encodes Marshaler values, or time values, structs, maps, slices, arrays and pointers by converting them with fromValue
+func (r *Encoder) encodeReflected(data interface{}) error {
+	if m, ok := data.(Marshaler); ok {
+		return m.MarshalRencode(r)
+	}
+	v, err := fromValue(reflect.ValueOf(data), StructOptions{TimeFormat: r.timeFormat})
+	if err != nil {
+		return err
+	}
+	return r.encodeSingle(v)
+}
@@ -0,0 +1,407 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type benchTorrentStatus struct {
+	Name           string
+	State          string
+	Progress       float64
+	TotalSize      int64
+	TotalDone      int64
+	NumSeeds       int
+	NumPeers       int
+	Eta            int64
+	Ratio          float64
+	IsFinished     bool
+	Paused         bool
+	SavePath       string
+	MaxDownload    float64 `rencode:"default=-1.0"`
+	Label          string  `rencode:"optional"`
+	Trackers       []benchTracker
+	FilePriorities []int8
+}
+
+type benchTracker struct {
+	URL  string
+	Tier int8
+}
+
+func benchTorrentDictionary(b testing.TB) Dictionary {
+	v, err := ParseText(`{
+		'name': 'ubuntu-20.04-desktop-amd64.iso', 'state': 'Seeding', 'progress': 100.0,
+		'total_size': 2715254784, 'total_done': 2715254784, 'num_seeds': 12, 'num_peers': 3,
+		'eta': 0, 'ratio': 1.25, 'is_finished': True, 'paused': False, 'save_path': '/downloads',
+		'trackers': [{'url': 'https://torrent.ubuntu.com/announce', 'tier': 0}],
+		'file_priorities': [1, 1, 0, 7],
+	}`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return v.(Dictionary)
+}
+
+func TestFromStruct(t *testing.T) {
+	t.Parallel()
+
+	d := benchTorrentDictionary(t)
+	var s benchTorrentStatus
+	err := d.ToStruct(&s, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.MaxDownload != -1 || s.Trackers[0].URL != "https://torrent.ubuntu.com/announce" {
+		t.Errorf("unexpected struct %+v", s)
+	}
+
+	out, err := FromStruct(&s, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again benchTorrentStatus
+	err = out.ToStruct(&again, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, again) {
+		t.Errorf("round-trip mismatch:\n%+v\n%+v", s, again)
+	}
+
+	type inner struct {
+		Seeds int
+	}
+	var nested struct {
+		Files  map[string]inner
+		Eta    *int64
+		Secret string                 `rencode:"private"`
+		Extra  map[string]interface{} `rencode:"remain"`
+		hidden int
+	}
+	nested.Files = map[string]inner{"b": {2}, "a": {1}}
+	nested.Secret = "x"
+	nested.Extra = map[string]interface{}{"other": []byte("y")}
+	out, err = FromStruct(nested, "private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(out) != "{'files': {'a': {'seeds': 1}, 'b': {'seeds': 2}}, 'eta': None, 'other': b'y'}" {
+		t.Errorf("unexpected dictionary %s", FormatText(out))
+	}
+
+	nested.Extra["eta"] = 1
+	if _, err := FromStruct(nested, "private"); err == nil {
+		t.Error("expected failure for duplicate remaining key")
+	}
+	if _, err := FromStruct(1, ""); err == nil {
+		t.Error("expected failure for non-struct value")
+	}
+}
+
+func TestEncodeStruct(t *testing.T) {
+	t.Parallel()
+
+	s := struct {
+		Name  string
+		Peers []benchTracker
+		Tags  [2]string
+		Done  *bool
+	}{Name: "a", Peers: []benchTracker{{"u", 1}}, Tags: [2]string{"x", "y"}}
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(s, &s, NewList(s.Peers[0]), map[int]string{2: "b", 1: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"{b'name': b'a', b'peers': [{b'url': b'u', b'tier': 1}], b'tags': [b'x', b'y'], b'done': None}",
+		"{b'name': b'a', b'peers': [{b'url': b'u', b'tier': 1}], b'tags': [b'x', b'y'], b'done': None}",
+		"[{b'url': b'u', b'tier': 1}]",
+		"{1: b'a', 2: b'b'}",
+	}
+	d := NewDecoder(&b)
+	for _, exp := range expected {
+		v, err := d.DecodeNext()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if FormatText(v) != exp {
+			t.Errorf("expected %s but got %s", exp, FormatText(v))
+		}
+	}
+
+	if err := e.Encode(make(chan int)); err == nil {
+		t.Error("expected failure for channel")
+	}
+}
+
+func TestEncodeStructUnsigned(t *testing.T) {
+	t.Parallel()
+
+	s := struct {
+		Port  uint16
+		Flags [2]byte
+		Size  uint32
+		Count uint
+	}{Port: 51413, Flags: [2]byte{200, 1}, Size: 4000000000, Count: 1}
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(s, uint8(200), uint16(51413), uint32(4000000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"{b'port': 51413, b'flags': [200, 1], b'size': 4000000000, b'count': 1}",
+		"200",
+		"51413",
+		"4000000000",
+	}
+	d := NewDecoder(&b)
+	for _, exp := range expected {
+		v, err := d.DecodeNext()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if FormatText(v) != exp {
+			t.Errorf("expected %s but got %s", exp, FormatText(v))
+		}
+	}
+}
+
+func TestStructCodecConcurrent(t *testing.T) {
+	t.Parallel()
+
+	d := benchTorrentDictionary(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var s benchTorrentStatus
+			if err := d.ToStruct(&s, ""); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkToStruct(b *testing.B) {
+	d := benchTorrentDictionary(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var s benchTorrentStatus
+		if err := d.ToStruct(&s, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkToStructUncached measures ToStruct when the struct descriptors are compiled on every call
+func BenchmarkToStructUncached(b *testing.B) {
+	d := benchTorrentDictionary(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		structCodecs.Delete(reflect.TypeOf(benchTorrentStatus{}))
+		structCodecs.Delete(reflect.TypeOf(benchTracker{}))
+		var s benchTorrentStatus
+		if err := d.ToStruct(&s, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFromStruct(b *testing.B) {
+	d := benchTorrentDictionary(b)
+	var s benchTorrentStatus
+	if err := d.ToStruct(&s, ""); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := FromStruct(&s, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type embeddedHeader struct {
+	ID      int
+	Version int
+}
+
+type EmbeddedMeta struct {
+	Source string
+	ID     string
+}
+
+type embeddedMessage struct {
+	embeddedHeader
+	*EmbeddedMeta
+	Body    string
+	Version string
+	Tracker benchTracker `rencode:"inline"`
+	Extra   EmbeddedMeta `rencode:"noinline"`
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	t.Parallel()
+
+	v, err := ParseText(`{'version': 'v2', 'source': 's', 'body': 'b', 'url': 'u', 'tier': 2,
+		'extra': {'source': 'x', 'id': 'y'}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := v.(Dictionary)
+
+	var m embeddedMessage
+	err = d.ToStruct(&m, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 'id' is ambiguous between the two embedded structs at the same depth, while
+	// 'version' of the outer struct hides the one of the embedded struct
+	d.Add("id", 1)
+	if err := d.ToStruct(&embeddedMessage{}, ""); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected unknown key error for ambiguous field but got %v", err)
+	}
+	if m.EmbeddedMeta == nil {
+		t.Error("expected embedded pointer to be allocated")
+	}
+	if m.Version != "v2" || m.Source != "s" || m.Body != "b" || m.Tracker.URL != "u" || m.Extra.ID != "y" {
+		t.Errorf("unexpected struct %+v", m)
+	}
+
+	out, err := FromStruct(m, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{'source': 's', 'body': 'b', 'version': 'v2', 'url': 'u', 'tier': 2, 'extra': {'source': 'x', 'id': 'y'}}"
+	if FormatText(out) != expected {
+		t.Errorf("expected %s but got %s", expected, FormatText(out))
+	}
+
+	// a nil embedded pointer is neither encoded nor allocated if none of its fields is present
+	m = embeddedMessage{}
+	out, err = FromStruct(m, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = out.ToStruct(&m, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Has("source") || m.EmbeddedMeta != nil {
+		t.Errorf("unexpected nil embedded pointer handling %s %+v", FormatText(out), m)
+	}
+
+	var excluded struct {
+		embeddedHeader `rencode:"private"`
+		Name           string
+	}
+	out, err = FromStruct(excluded, "private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(out) != "{'name': ''}" {
+		t.Errorf("unexpected dictionary %s", FormatText(out))
+	}
+
+	var badInline struct {
+		Name string `rencode:"inline"`
+	}
+	if _, err := FromStruct(badInline, ""); err == nil {
+		t.Error("expected failure for inlined string field")
+	}
+}
+
+type embeddedC struct {
+	X int
+}
+
+type embeddedA struct {
+	embeddedC
+}
+
+type embeddedB struct {
+	embeddedC
+}
+
+func TestEmbeddedSameStructTwice(t *testing.T) {
+	t.Parallel()
+
+	// like encoding/json, the fields of a struct embedded twice at the same depth hide each other
+	out, err := FromStruct(struct {
+		embeddedA
+		embeddedB
+	}{embeddedA{embeddedC{1}}, embeddedB{embeddedC{2}}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(out) != "{}" {
+		t.Errorf("unexpected dictionary %s", FormatText(out))
+	}
+
+	out, err = FromStruct(struct {
+		embeddedC
+		*embeddedA
+	}{embeddedC{1}, nil}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(out) != "{'x': 1}" {
+		t.Errorf("unexpected dictionary %s", FormatText(out))
+	}
+}
+
+func TestToValue(t *testing.T) {
+	t.Parallel()
+
+	v, err := ToValue(map[interface{}]interface{}{
+		"b":     []string{"x"},
+		"a":     map[bool]uint8{true: 1, false: 2},
+		"c":     (*int)(nil),
+		int8(1): 1.5,
+	}, StructOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(v) != "{1: 1.5, 'a': {False: 2, True: 1}, 'b': ['x'], 'c': None}" {
+		t.Errorf("unexpected value %s", FormatText(v))
+	}
+
+	_, err = ToValue(make(chan int), StructOptions{})
+	if err == nil {
+		t.Error("expected failure for channel")
+	}
+}
@@ -0,0 +1,108 @@
+#!/usr/bin/env python3
+#
+# Generates the golden vectors of vectors.txt with the reference Python implementation
+# (https://github.com/aresch/rencode), which must be installed.
+#
+# Usage:
+#
+#     python3 generate.py > vectors.txt     write the vectors
+#     python3 generate.py --check           compare vectors.txt with the rencode package output
+#
+# Each vector line of vectors.txt has three tab-separated fields: the hexadecimal encoding, the
+# float bits passed to dumps() and the Python literal of the value. An encoding of "error" means
+# that dumps() rejects the value; a literal of "error" means that loads() rejects the encoding.
+# Lines starting with # are comments; the first one records the rencode version used.
+
+import os
+import sys
+
+import rencode
+
+MAX_INT_LENGTH = 64
+CHR_INT = 61
+CHR_TERM = 127
+
+
+# values at every type code boundary, with the float bits to use
+VECTORS = [(v, 32) for v in [
+    # embedded positive and negative integers
+    0, 1, 43, 44, -1, -32, -33,
+    # CHR_INT1, CHR_INT2, CHR_INT4, CHR_INT8
+    127, -128, 128, -129, 32767, -32768, 32768, -32769,
+    2147483647, -2147483648, 2147483648, -2147483649,
+    9223372036854775807, -9223372036854775808,
+    # CHR_INT
+    9223372036854775808, -9223372036854775809, 18446744073709551615, 18446744073709551616,
+    10 ** 62, -(10 ** 61),
+    # integers of MAX_INT_LENGTH characters are rejected
+    10 ** (MAX_INT_LENGTH - 1), -(10 ** (MAX_INT_LENGTH - 2)),
+    # CHR_FLOAT32
+    0.5, -1.5, 0.0, 16777216.0, 3.4028234663852886e+38, 1.401298464324817e-45,
+    # CHR_NONE, CHR_TRUE, CHR_FALSE
+    None, True, False,
+    # embedded and prefixed strings
+    b'', b'a', b'\x00\xff', 'fööbar', b'x' * 63, b'x' * 64, b'y' * 65, b'z' * 1000, 'é' * 40,
+    # embedded and terminated lists
+    [], [1], (1, b'a', None), list(range(63)), list(range(64)), list(range(100)),
+    # embedded and terminated dictionaries
+    {}, {b'a': 1}, {1: b'one', False: None, None: 2.5},
+    dict((b'k%d' % i, i) for i in range(24)),
+    dict((b'k%d' % i, i) for i in range(25)),
+    dict((i, [i]) for i in range(30)),
+    # nested values
+    {b'torrents': {b'0123456789abcdef0123456789abcdef01234567': {b'name': 'ubuntu.iso', b'progress': 12.5,
+                                                                b'files': [{b'path': b'a/b', b'size': 4294967296}]}}},
+    [[[[]]], {b'': {}}],
+]] + [(v, 64) for v in [
+    # CHR_FLOAT64
+    0.1, -0.0, 1e300, -2.5e-308, 1234.56, 5e-324,
+    [0.1, {b'ratio': 1.0 / 3}],
+]]
+
+
+# encodings at the decoding limits
+DECODE_VECTORS = [
+    bytes([CHR_INT]) + b'1' * (MAX_INT_LENGTH - 1) + bytes([CHR_TERM]),
+    bytes([CHR_INT]) + b'1' * MAX_INT_LENGTH + bytes([CHR_TERM]),
+    bytes([CHR_INT]) + b'-' + b'1' * (MAX_INT_LENGTH - 1) + bytes([CHR_TERM]),
+]
+
+
+def encode_vector(v, bits):
+    try:
+        encoded = rencode.dumps(v, bits).hex()
+    except ValueError:
+        encoded = 'error'
+    return '%s\t%d\t%r' % (encoded, bits, v)
+
+
+def decode_vector(b):
+    try:
+        literal = repr(rencode.loads(b))
+    except ValueError:
+        literal = 'error'
+    return '%s\t-\t%s' % (b.hex(), literal)
+
+
+def main():
+    lines = [encode_vector(v, bits) for v, bits in VECTORS] + [decode_vector(b) for b in DECODE_VECTORS]
+
+    if '--check' in sys.argv:
+        path = os.path.join(os.path.dirname(os.path.abspath(__file__)), 'vectors.txt')
+        with open(path) as f:
+            expected = [line for line in f.read().splitlines() if not line.startswith('#')]
+        if expected != lines:
+            for i, (a, b) in enumerate(zip(expected, lines)):
+                if a != b:
+                    sys.exit('vector %d differs:\n%s\n%s' % (i, a, b))
+            sys.exit('vectors count differs: %d != %d' % (len(expected), len(lines)))
+        print('%d vectors ok' % len(lines))
+        return
+
+    print('# generated by generate.py with rencode %s' % getattr(rencode, '__version__', 'unknown'))
+    for line in lines:
+        print(line)
+
+
+if __name__ == '__main__':
+    main()
@@ -0,0 +1,564 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+ParseText and FormatText use the syntax of Python literals, as printed by a Python rencode session:
+
+	None, True, False
+	1, -7, 18446744073709551616          integers, returned as the narrowest type like DecodeNext
+	2.5, -1e-05, inf, nan                floats, returned as float64
+	b'x\x00', 'text', "text"             strings, returned as []byte
+	[1, 2], (1, 2), (1,)                 lists and tuples, returned as List
+	{1, 2}, set()                        sets, returned as List
+	{'a': 1}                             dictionaries, returned as Dictionary
+
+ParseTypedText returns tuples as Tuple and sets as Set instead, and FormatText prints them with
+the same syntax, so that the Python type of values survives a text round trip.
+
+String literals support the \\, \', \", \n, \r, \t and \xhh escapes; non-byte strings also
+support \uhhhh and \Uhhhhhhhh. Code points of non-byte strings are encoded as UTF-8.
+*/
+
+type textParser struct {
+	s   string
+	pos int
+	// typed is set to return tuples and sets as Tuple and Set
+	typed bool
+}
+
+// ParseText parses a single value written with the Python literal syntax
+func ParseText(s string) (interface{}, error) {
+	return parseText(textParser{s: s})
+}
+
+// ParseTypedText is like ParseText, but returns tuples as Tuple and sets as Set
+func ParseTypedText(s string) (interface{}, error) {
+	return parseText(textParser{s: s, typed: true})
+}
+
+func parseText(p textParser) (interface{}, error) {
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected %q after value", p.s[p.pos])
+	}
+	return v, nil
+}
+
+func (p *textParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("text offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *textParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space character, or 0 at the end of input
+func (p *textParser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *textParser) expect(c byte) error {
+	if p.peek() != c {
+		if p.pos == len(p.s) {
+			return p.errorf("expected %q but reached end of input", c)
+		}
+		return p.errorf("expected %q but found %q", c, p.s[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+func (p *textParser) value() (interface{}, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, p.errorf("unexpected end of input")
+	case c == '[':
+		p.pos++
+		return p.list(']')
+	case c == '(':
+		p.pos++
+		return p.tuple()
+	case c == '{':
+		p.pos++
+		return p.dictOrSet()
+	case c == '\'' || c == '"':
+		return p.str(false)
+	case (c == 'b' || c == 'B') && p.pos+1 < len(p.s) && (p.s[p.pos+1] == '\'' || p.s[p.pos+1] == '"'):
+		p.pos++
+		return p.str(true)
+	case c == '-' || c == '+' || c == '.' || ('0' <= c && c <= '9'):
+		return p.number()
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && isIdentByte(p.s[p.pos]) {
+		p.pos++
+	}
+	switch p.s[start:p.pos] {
+	case "None":
+		return nil, nil
+	case "True":
+		return true, nil
+	case "False":
+		return false, nil
+	case "inf":
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	case "set":
+		if strings.HasPrefix(p.s[p.pos:], "()") {
+			p.pos += 2
+			return p.set(List{})
+		}
+	}
+	p.pos = start
+	return nil, p.errorf("unexpected %q", c)
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// list parses the elements of a list up to the closing character
+func (p *textParser) list(closing byte) (List, error) {
+	var l List
+	for {
+		if p.peek() == closing {
+			p.pos++
+			return l, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return l, err
+		}
+		l.Add(v)
+		if p.peek() != ',' {
+			return l, p.expect(closing)
+		}
+		p.pos++
+	}
+}
+
+// tuple parses a tuple or a parenthesized value
+func (p *textParser) tuple() (interface{}, error) {
+	if p.peek() == ')' {
+		p.pos++
+		return p.tupleOf(List{}), nil
+	}
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ',' {
+		return v, p.expect(')')
+	}
+	p.pos++
+
+	l, err := p.list(')')
+	if err != nil {
+		return nil, err
+	}
+	return p.tupleOf(NewList(append([]interface{}{v}, l.Values()...)...)), nil
+}
+
+func (p *textParser) tupleOf(l List) interface{} {
+	if p.typed {
+		return l.ToTuple()
+	}
+	return l
+}
+
+// dictOrSet parses a dictionary or, if the first element is not followed by a colon, a set
+func (p *textParser) dictOrSet() (interface{}, error) {
+	if p.peek() == '}' {
+		p.pos++
+		return Dictionary{}, nil
+	}
+	first, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() == ':' {
+		return p.dict(first)
+	}
+
+	l := NewList(first)
+	if p.peek() != ',' {
+		err = p.expect('}')
+	} else {
+		p.pos++
+		var rest List
+		rest, err = p.list('}')
+		l.values = append(l.values, rest.values...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.set(l)
+}
+
+func (p *textParser) set(l List) (interface{}, error) {
+	if !p.typed {
+		return l, nil
+	}
+	s, err := l.ToSet()
+	if err != nil {
+		return nil, p.errorf("set: %v", err)
+	}
+	return s, nil
+}
+
+// dict parses the entries of a dictionary, whose first key has already been parsed
+func (p *textParser) dict(k interface{}) (Dictionary, error) {
+	var d Dictionary
+	for {
+		err := p.expect(':')
+		if err != nil {
+			return d, err
+		}
+		v, err := p.value()
+		if err != nil {
+			return d, err
+		}
+		d.Add(k, v)
+		if p.peek() != ',' {
+			return d, p.expect('}')
+		}
+		p.pos++
+		if p.peek() == '}' {
+			p.pos++
+			return d, nil
+		}
+		k, err = p.value()
+		if err != nil {
+			return d, err
+		}
+	}
+}
+
+func (p *textParser) number() (interface{}, error) {
+	start := p.pos
+	if p.s[p.pos] == '-' || p.s[p.pos] == '+' {
+		p.pos++
+	}
+	if strings.HasPrefix(p.s[p.pos:], "inf") {
+		p.pos += len("inf")
+		return strconv.ParseFloat(p.s[start:p.pos], 64)
+	}
+
+	isFloat := false
+	for ; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		if c == '.' || c == 'e' || c == 'E' {
+			isFloat = true
+		} else if (c == '-' || c == '+') && (p.s[p.pos-1] == 'e' || p.s[p.pos-1] == 'E') {
+			continue
+		} else if c < '0' || c > '9' {
+			break
+		}
+	}
+
+	s := p.s[start:p.pos]
+	if isFloat {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid float %q", s)
+		}
+		return f, nil
+	}
+	v, err := narrowInteger(strings.TrimPrefix(s, "+"))
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid integer %q", s)
+	}
+	return v, nil
+}
+
+// str parses a quoted string literal
+func (p *textParser) str(isBytes bool) ([]byte, error) {
+	quote := p.s[p.pos]
+	p.pos++
+
+	var b []byte
+	for {
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("unterminated string")
+		}
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b, nil
+		case c == '\n':
+			return nil, p.errorf("newline in string")
+		case isBytes && c >= utf8.RuneSelf:
+			return nil, p.errorf("non-ASCII character in bytes literal")
+		case c != '\\':
+			b = append(b, c)
+			continue
+		}
+
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("unterminated string")
+		}
+		c = p.s[p.pos]
+		p.pos++
+		switch c {
+		case '\\', '\'', '"':
+			b = append(b, c)
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'x', 'u', 'U':
+			if isBytes && c != 'x' {
+				return nil, p.errorf("invalid escape \\%c in bytes literal", c)
+			}
+			size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+			v, err := p.hex(size)
+			if err != nil {
+				return nil, err
+			}
+			if isBytes {
+				b = append(b, byte(v))
+				break
+			}
+			if !utf8.ValidRune(rune(v)) {
+				return nil, p.errorf("invalid code point %x", v)
+			}
+			b = append(b, string(rune(v))...)
+		default:
+			return nil, p.errorf("invalid escape \\%c", c)
+		}
+	}
+}
+
+func (p *textParser) hex(size int) (uint64, error) {
+	if p.pos+size > len(p.s) {
+		return 0, p.errorf("truncated escape sequence")
+	}
+	v, err := strconv.ParseUint(p.s[p.pos:p.pos+size], 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid escape sequence %q", p.s[p.pos:p.pos+size])
+	}
+	p.pos += size
+	return v, nil
+}
+
+// FormatText returns the representation of a value in the style of Python repr; []byte values are
+// printed as bytes literals and valid UTF-8 strings as str literals. Values of unsupported types are printed
+// with the default fmt formatting.
+func FormatText(v interface{}) string {
+	var b bytes.Buffer
+	formatText(&b, v)
+	return b.String()
+}
+
+func formatText(b *bytes.Buffer, v interface{}) {
+	switch x := v.(type) {
+	case nil:
+		b.WriteString("None")
+	case bool:
+		if x {
+			b.WriteString("True")
+		} else {
+			b.WriteString("False")
+		}
+	case float32:
+		b.WriteString(formatFloat(float64(x), 32))
+	case float64:
+		b.WriteString(formatFloat(x, 64))
+	case big.Int:
+		b.WriteString(x.String())
+	case *big.Int:
+		b.WriteString(x.String())
+	case []byte:
+		b.WriteByte('b')
+		quoteText(b, x, true)
+	case string:
+		if !utf8.ValidString(x) {
+			b.WriteByte('b')
+		}
+		quoteText(b, []byte(x), !utf8.ValidString(x))
+	case List:
+		formatList(b, x.Values())
+	case *List:
+		formatList(b, x.Values())
+	case Tuple:
+		formatTuple(b, x.Values())
+	case *Tuple:
+		formatTuple(b, x.Values())
+	case Set:
+		formatSet(b, x.Values())
+	case *Set:
+		formatSet(b, x.Values())
+	case Dictionary:
+		formatDict(b, &x)
+	case *Dictionary:
+		formatDict(b, x)
+	default:
+		fmt.Fprint(b, v)
+	}
+}
+
+func formatList(b *bytes.Buffer, values []interface{}) {
+	b.WriteByte('[')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		formatText(b, v)
+	}
+	b.WriteByte(']')
+}
+
+func formatTuple(b *bytes.Buffer, values []interface{}) {
+	b.WriteByte('(')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		formatText(b, v)
+	}
+	if len(values) == 1 {
+		b.WriteByte(',')
+	}
+	b.WriteByte(')')
+}
+
+func formatSet(b *bytes.Buffer, values []interface{}) {
+	if len(values) == 0 {
+		b.WriteString("set()")
+		return
+	}
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		formatText(b, v)
+	}
+	b.WriteByte('}')
+}
+
+func formatDict(b *bytes.Buffer, d *Dictionary) {
+	values := d.Values()
+	b.WriteByte('{')
+	for i, k := range d.Keys() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		formatText(b, k)
+		b.WriteString(": ")
+		formatText(b, values[i])
+	}
+	b.WriteByte('}')
+}
+
+// formatFloat formats a float like Python repr: shortest representation, with exponent
+// only for very small or large values and always with a decimal point otherwise.
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+
+	s := strconv.FormatFloat(f, 'e', -1, bitSize)
+	exp, _ := strconv.Atoi(s[strings.IndexByte(s, 'e')+1:])
+	if exp < -4 || exp >= 16 {
+		return s
+	}
+	s = strconv.FormatFloat(f, 'f', -1, bitSize)
+	if strings.IndexByte(s, '.') < 0 {
+		s += ".0"
+	}
+	return s
+}
+
+// quoteText writes a quoted string literal, preferring single quotes like Python
+func quoteText(b *bytes.Buffer, s []byte, isBytes bool) {
+	quote := byte('\'')
+	if bytes.IndexByte(s, '\'') >= 0 && bytes.IndexByte(s, '"') < 0 {
+		quote = '"'
+	}
+
+	b.WriteByte(quote)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == quote || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case 0x20 <= c && c < 0x7f:
+			b.WriteByte(c)
+		case !isBytes && c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(s[i:])
+			switch {
+			case strconv.IsPrint(r):
+				b.Write(s[i : i+size])
+			case r > 0xffff:
+				fmt.Fprintf(b, `\U%08x`, r)
+			default:
+				fmt.Fprintf(b, `\u%04x`, r)
+			}
+			i += size
+			continue
+		default:
+			fmt.Fprintf(b, `\x%02x`, c)
+		}
+		i++
+	}
+	b.WriteByte(quote)
+}
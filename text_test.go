@@ -0,0 +1,162 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseText(t *testing.T) {
+	t.Parallel()
+
+	v, err := ParseText(`{'a': [1, 2.5, b'x', None, True], "big": -18446744073709551616, 'tuple': (1,), 'u': 'é'}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var list List
+	list.Add(int8(1), 2.5, []byte("x"), nil, true)
+	var expected Dictionary
+	expected.Add([]byte("a"), list)
+	expected.Add([]byte("big"), bigInt(t, "-18446744073709551616"))
+	expected.Add([]byte("tuple"), NewList(int8(1)))
+	expected.Add([]byte("u"), []byte("é"))
+
+	var b1, b2 bytes.Buffer
+	e := NewEncoder(&b1)
+	err = e.Encode(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = NewEncoder(&b2)
+	err = e.Encode(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b1.Bytes(), b2.Bytes()) {
+		t.Errorf("unexpected value %s", FormatText(v))
+	}
+
+	d := NewDecoder(&b1)
+	decoded, err := d.DecodeNext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict := decoded.(Dictionary)
+	a, _ := dict.Get("a")
+	l := a.(List)
+	if l.Values()[0] != int8(1) {
+		t.Errorf("expected int8 but got %T", l.Values()[0])
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	t.Parallel()
+
+	var d Dictionary
+	d.Add("name", []byte("it's\x00"))
+	d.Add(int8(-3), NewList(float32(0.1), 1e20, 3.0, math.Inf(-1), false, nil))
+	d.Add(bigInt(t, "18446744073709551616"), "π\n")
+
+	expected := `{'name': b"it's\x00", -3: [0.1, 1e+20, 3.0, -inf, False, None], 18446744073709551616: 'π\n'}`
+	s := FormatText(d)
+	if s != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, s)
+	}
+
+	v, err := ParseText(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(v) != `{b'name': b"it's\x00", -3: [0.1, 1e+20, 3.0, -inf, False, None], 18446744073709551616: b'\xcf\x80\n'}` {
+		t.Errorf("unexpected formatting of parsed value: %s", FormatText(v))
+	}
+}
+
+func TestParseTextInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"",
+		"[1, 2",
+		"{'a' 1}",
+		"'abc",
+		`b'é'`,
+		`'\q'`,
+		"1 2",
+		"none",
+		"1.2.3",
+		"[,]",
+	} {
+		_, err := ParseText(input)
+		if err == nil {
+			t.Errorf("expected failure for %q", input)
+		}
+	}
+}
+
+func TestParseTextNestedSets(t *testing.T) {
+	t.Parallel()
+
+	// each element of a dictionary or set is parsed once, whatever the nesting depth
+	const depth = 200
+	s := strings.Repeat("{", depth) + "1" + strings.Repeat("}", depth)
+	v, err := ParseText(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(v) != strings.Repeat("[", depth)+"1"+strings.Repeat("]", depth) {
+		t.Errorf("unexpected value %s", FormatText(v))
+	}
+
+	for input, expected := range map[string]string{
+		"{}":             "{}",
+		"{1,}":           "[1]",
+		"{1, 2}":         "[1, 2]",
+		"{1: 2,}":        "{1: 2}",
+		"{1: {2}, 3: 4}": "{1: [2], 3: 4}",
+	} {
+		v, err := ParseText(input)
+		if err != nil {
+			t.Errorf("%q: %v", input, err)
+			continue
+		}
+		if FormatText(v) != expected {
+			t.Errorf("%q: expected %s but got %s", input, expected, FormatText(v))
+		}
+	}
+	for _, input := range []string{"{1: 2, 3}", "{1, 2: 3}", "{1", "{1: 2"} {
+		if _, err := ParseText(input); err == nil {
+			t.Errorf("expected failure for %q", input)
+		}
+	}
+}
+
+func bigInt(t *testing.T, s string) big.Int {
+	var i big.Int
+	if _, ok := i.SetString(s, 10); !ok {
+		t.Fatalf("invalid integer %q", s)
+	}
+	return i
+}
@@ -0,0 +1,170 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// TimeFormat specifies how time.Time and time.Duration values are represented.
+// When decoding, floats are always taken as seconds and byte strings as text, while integers
+// are taken as nanoseconds only with TimeUnixNanos and as seconds otherwise.
+type TimeFormat int
+
+const (
+	// TimeFloatSeconds represents times as float64 seconds since the Unix epoch and durations as
+	// float64 seconds, as Deluge does; this is the default
+	TimeFloatSeconds TimeFormat = iota
+	// TimeUnixSeconds represents times as int64 seconds since the Unix epoch and durations as int64 seconds
+	TimeUnixSeconds
+	// TimeUnixNanos represents times as int64 nanoseconds since the Unix epoch and durations as int64 nanoseconds
+	TimeUnixNanos
+	// TimeRFC3339 represents times as RFC 3339 byte strings with nanoseconds and durations as
+	// byte strings formatted by time.Duration.String
+	TimeRFC3339
+)
+
+var timeFormatNames = map[string]TimeFormat{
+	"float":    TimeFloatSeconds,
+	"unix":     TimeUnixSeconds,
+	"unixnano": TimeUnixNanos,
+	"rfc3339":  TimeRFC3339,
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// timeValue returns the representation of a time.Time or time.Duration value
+func timeValue(v interface{}, format TimeFormat) (interface{}, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		switch format {
+		case TimeUnixSeconds:
+			return x.Unix(), true
+		case TimeUnixNanos:
+			return x.UnixNano(), true
+		case TimeRFC3339:
+			return x.Format(time.RFC3339Nano), true
+		}
+		return float64(x.Unix()) + float64(x.Nanosecond())/1e9, true
+	case time.Duration:
+		switch format {
+		case TimeUnixSeconds:
+			return int64(x / time.Second), true
+		case TimeUnixNanos:
+			return int64(x), true
+		case TimeRFC3339:
+			return x.String(), true
+		}
+		return x.Seconds(), true
+	}
+	return nil, false
+}
+
+// assignTime stores src into dest if it is a *time.Time or *time.Duration, returning false otherwise
+func assignTime(src, dest interface{}, format TimeFormat) (bool, error) {
+	switch dest := dest.(type) {
+	case *time.Time:
+		if b, ok := timeText(src); ok {
+			t, err := time.Parse(time.RFC3339Nano, b)
+			if err != nil {
+				return true, err
+			}
+			*dest = t
+			return true, nil
+		}
+		if f, ok := timeFloat(src); ok {
+			sec := math.Floor(f)
+			*dest = time.Unix(int64(sec), int64(math.Round((f-sec)*1e9)))
+			return true, nil
+		}
+		var n int64
+		err := convertAssign(src, &n)
+		if err != nil {
+			return true, fmt.Errorf("cannot convert from %T into %T", src, dest)
+		}
+		if format == TimeUnixNanos {
+			*dest = time.Unix(0, n)
+		} else {
+			*dest = time.Unix(n, 0)
+		}
+		return true, nil
+	case *time.Duration:
+		if b, ok := timeText(src); ok {
+			d, err := time.ParseDuration(b)
+			if err != nil {
+				return true, err
+			}
+			*dest = d
+			return true, nil
+		}
+		if f, ok := timeFloat(src); ok {
+			*dest = time.Duration(math.Round(f * float64(time.Second)))
+			return true, nil
+		}
+		var n int64
+		err := convertAssign(src, &n)
+		if err != nil {
+			return true, fmt.Errorf("cannot convert from %T into %T", src, dest)
+		}
+		if format == TimeUnixNanos {
+			*dest = time.Duration(n)
+		} else {
+			*dest = time.Duration(n) * time.Second
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func timeText(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case []byte:
+		return string(x), true
+	case string:
+		return x, true
+	}
+	return "", false
+}
+
+func timeFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
+
+// SetTimeFormat sets the representation of the time.Time and time.Duration values encoded by Encode
+func (r *Encoder) SetTimeFormat(format TimeFormat) {
+	r.timeFormat = format
+}
+
+// SetTimeFormat sets the representation of the integers scanned into time.Time and time.Duration values
+func (r *Decoder) SetTimeFormat(format TimeFormat) {
+	r.timeFormat = format
+}
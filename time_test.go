@@ -0,0 +1,138 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeFormats(t *testing.T) {
+	t.Parallel()
+
+	added := time.Date(2020, 9, 13, 12, 26, 40, 500000000, time.UTC)
+	eta := 90 * time.Minute
+
+	for _, testCase := range []struct {
+		format   TimeFormat
+		expected string
+	}{
+		{TimeFloatSeconds, "[1600000000.5, 5400.0]"},
+		{TimeUnixSeconds, "[1600000000, 5400]"},
+		{TimeUnixNanos, "[1600000000500000000, 5400000000000]"},
+		{TimeRFC3339, "[b'2020-09-13T12:26:40.5Z', b'1h30m0s']"},
+	} {
+		var b bytes.Buffer
+		e := NewEncoder(&b)
+		e.SetTimeFormat(testCase.format)
+		err := e.Encode(NewList(added, eta))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := NewDecoder(bytes.NewReader(b.Bytes()))
+		v, err := d.DecodeNext()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if FormatText(v) != testCase.expected {
+			t.Errorf("format %d: expected %s but got %s", testCase.format, testCase.expected, FormatText(v))
+		}
+
+		// the list elements are scanned back
+		d = NewDecoder(bytes.NewReader(b.Bytes()[1:]))
+		d.SetTimeFormat(testCase.format)
+		var scannedTime time.Time
+		var scannedDuration time.Duration
+		err = d.Scan(&scannedTime, &scannedDuration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedTime := added
+		if testCase.format == TimeUnixSeconds {
+			expectedTime = added.Truncate(time.Second)
+		}
+		if !scannedTime.Equal(expectedTime) || scannedDuration != eta {
+			t.Errorf("format %d: unexpected values %v %v", testCase.format, scannedTime, scannedDuration)
+		}
+	}
+
+	var l List
+	l.Add(1.5)
+	var dur time.Duration
+	if err := l.Scan(&dur); err != nil || dur != 1500*time.Millisecond {
+		t.Errorf("unexpected duration %v (%v)", dur, err)
+	}
+	l = NewList(NewList())
+	if err := l.Scan(&dur); err == nil {
+		t.Error("expected failure for list into duration")
+	}
+}
+
+func TestStructTimes(t *testing.T) {
+	t.Parallel()
+
+	type status struct {
+		TimeAdded    time.Time
+		LastSeen     time.Time `rencode:"time=unix"`
+		Eta          time.Duration
+		SeedingTimes []time.Duration `rencode:"time=unixnano"`
+		Completed    *time.Time      `rencode:"time=rfc3339"`
+	}
+
+	v, err := ParseText(`{'time_added': 1600000000.25, 'last_seen': 1600000100, 'eta': 60,
+		'seeding_times': [1000, 2000], 'completed': '2020-09-13T12:26:40Z'}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := v.(Dictionary)
+
+	var s status
+	err = d.ToStruct(&s, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.TimeAdded.Equal(time.Unix(1600000000, 250000000)) || !s.LastSeen.Equal(time.Unix(1600000100, 0)) {
+		t.Errorf("unexpected times %v %v", s.TimeAdded, s.LastSeen)
+	}
+	if s.Eta != time.Minute || len(s.SeedingTimes) != 2 || s.SeedingTimes[1] != 2000 {
+		t.Errorf("unexpected durations %v %v", s.Eta, s.SeedingTimes)
+	}
+	if s.Completed == nil || !s.Completed.Equal(time.Unix(1600000000, 0)) {
+		t.Errorf("unexpected completion time %v", s.Completed)
+	}
+
+	out, err := FromStructWithOptions(s, StructOptions{TimeFormat: TimeUnixSeconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{'time_added': 1600000000, 'last_seen': 1600000100, 'eta': 60, 'seeding_times': [1000, 2000], 'completed': '2020-09-13T12:26:40Z'}"
+	if FormatText(out) != expected {
+		t.Errorf("expected %s but got %s", expected, FormatText(out))
+	}
+
+	var bad struct {
+		Added time.Time `rencode:"time=iso"`
+	}
+	if err := d.ToStructWithOptions(&bad, StructOptions{IgnoreUnknownKeys: true}); err == nil {
+		t.Error("expected failure for invalid time format")
+	}
+}
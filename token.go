@@ -0,0 +1,86 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import "io"
+
+// TokenKind identifies the kind of a Token
+type TokenKind int
+
+const (
+	// ValueToken is a scalar value: integer, float, boolean, None or byte string
+	ValueToken TokenKind = iota
+	// ListToken starts a list
+	ListToken
+	// DictToken starts a dictionary; keys and values follow as alternating tokens
+	DictToken
+	// EndToken terminates a list or dictionary which has been started with a Length of -1
+	EndToken
+)
+
+// Token is a single element of a rencode stream, as returned by NextToken
+type Token struct {
+	Kind TokenKind
+	// Code is the type code which introduced the token
+	Code byte
+	// Offset is the position of the type code in the stream
+	Offset int64
+	// Length is the count of elements of a list (or of key/value pairs of a dictionary)
+	// with embedded length, or -1 if the container is terminated by an EndToken
+	Length int
+	// Value is the decoded value of a ValueToken
+	Value interface{}
+}
+
+// NextToken returns the next token in the rencode stream, without decoding the values
+// contained in lists and dictionaries; these are returned by the following calls.
+// If no more tokens are available, an io.EOF error will be returned.
+func (r *Decoder) NextToken() (Token, error) {
+	t := Token{Offset: r.offset}
+	typeCode, err := r.readByte()
+	if err != nil {
+		return t, err
+	}
+	t.Code = typeCode
+
+	switch {
+	case typeCode == CHR_LIST:
+		t.Kind = ListToken
+		t.Length = -1
+	case typeCode == CHR_DICT:
+		t.Kind = DictToken
+		t.Length = -1
+	case typeCode == CHR_TERM:
+		t.Kind = EndToken
+	case typeCode >= LIST_FIXED_START:
+		t.Kind = ListToken
+		t.Length = int(typeCode - LIST_FIXED_START)
+	case DICT_FIXED_START <= typeCode && typeCode < DICT_FIXED_START+DICT_FIXED_COUNT:
+		t.Kind = DictToken
+		t.Length = int(typeCode - DICT_FIXED_START)
+	default:
+		t.Value, err = r.decode(typeCode)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+
+	return t, err
+}
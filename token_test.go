@@ -0,0 +1,113 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestNextToken(t *testing.T) {
+	t.Parallel()
+
+	var long List
+	for i := 0; i < LIST_FIXED_COUNT; i++ {
+		long.Add(int8(i % 10))
+	}
+
+	var d Dictionary
+	d.Add("alpha", int16(1234))
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err := e.Encode(NewList(true, "x"), d, long)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := int64(b.Len())
+	dec := NewDecoder(&b)
+	expected := []struct {
+		kind   TokenKind
+		offset int64
+		length int
+		value  interface{}
+	}{
+		{ListToken, 0, 2, nil},
+		{ValueToken, 1, 0, true},
+		{ValueToken, 2, 0, "x"},
+		{DictToken, 4, 1, nil},
+		{ValueToken, 5, 0, "alpha"},
+		{ValueToken, 11, 0, int16(1234)},
+		{ListToken, 14, -1, nil},
+	}
+	for i, exp := range expected {
+		tok, err := dec.NextToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind != exp.kind || tok.Offset != exp.offset || tok.Length != exp.length {
+			t.Fatalf("token %d: expected %v/%d/%d but got %v/%d/%d", i, exp.kind, exp.offset, exp.length, tok.Kind, tok.Offset, tok.Length)
+		}
+		if b, ok := tok.Value.([]byte); ok {
+			tok.Value = string(b)
+		}
+		if tok.Value != exp.value {
+			t.Errorf("token %d: expected value %v but %v found", i, exp.value, tok.Value)
+		}
+	}
+
+	for i := 0; i < LIST_FIXED_COUNT; i++ {
+		tok, err := dec.NextToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind != ValueToken {
+			t.Fatalf("expected a value but got %v", tok.Kind)
+		}
+	}
+	tok, err := dec.NextToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Kind != EndToken {
+		t.Fatalf("expected end of list but got %v", tok.Kind)
+	}
+	_, err = dec.NextToken()
+	if err != io.EOF {
+		t.Fatalf("expected EOF but got %v", err)
+	}
+	if dec.Offset() != total {
+		t.Errorf("unexpected final offset %d", dec.Offset())
+	}
+}
+
+func TestInvalidTypeCode(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []byte{45, '0', ':', CHR_TERM} {
+		d := NewDecoder(bytes.NewReader([]byte{code}))
+		_, err := d.DecodeNext()
+		if err == nil {
+			t.Errorf("expected failure for type code %d", code)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// ErrUnhashable is the error returned when a value which cannot be an element of a Set is added to it
+var ErrUnhashable = errors.New("unhashable value")
+
+// Tuple is a list of values presented as a Python tuple; since rencode has no tuple type
+// it is encoded as a list, and decoded lists can be converted with List.ToTuple.
+type Tuple struct {
+	List
+}
+
+// NewTuple returns a new tuple with the values specified as arguments
+func NewTuple(values ...interface{}) Tuple {
+	return Tuple{NewList(values...)}
+}
+
+// Set is a collection of distinct values presented as a Python set; it is encoded as a list
+// and decoded lists can be converted with List.ToSet. Values are kept in insertion order.
+// Elements can be nil, booleans, numbers, strings and byte slices, which are equal to strings
+// with the same content, or tuples of such values; as in Python, numbers are equal if they have
+// the same value, regardless of their type.
+type Set struct {
+	values []interface{}
+}
+
+// NewSet returns a new set with the values specified as arguments; duplicate values are ignored.
+func NewSet(values ...interface{}) (Set, error) {
+	var s Set
+	err := s.Add(values...)
+	return s, err
+}
+
+// Add adds one or more values which are not in the set yet
+func (s *Set) Add(values ...interface{}) error {
+	for _, v := range values {
+		if !hashable(v) {
+			return ErrUnhashable
+		}
+	}
+	for _, v := range values {
+		if !s.Has(v) {
+			s.values = append(s.values, v)
+		}
+	}
+	return nil
+}
+
+// Has returns true if the value is in the set
+func (s *Set) Has(value interface{}) bool {
+	return s.find(value) >= 0
+}
+
+// Delete removes a value from the set and returns true if it was found
+func (s *Set) Delete(value interface{}) bool {
+	i := s.find(value)
+	if i < 0 {
+		return false
+	}
+	n := make([]interface{}, 0, len(s.values)-1)
+	n = append(n, s.values[:i]...)
+	s.values = append(n, s.values[i+1:]...)
+	return true
+}
+
+// Values returns all values in the set
+func (s *Set) Values() []interface{} {
+	return s.values
+}
+
+// Length returns the total count of elements
+func (s *Set) Length() int {
+	return len(s.values)
+}
+
+// List returns the values of the set as a list
+func (s *Set) List() List {
+	return NewList(append([]interface{}(nil), s.values...)...)
+}
+
+func (s *Set) find(value interface{}) int {
+	if !hashable(value) {
+		return -1
+	}
+	for i, v := range s.values {
+		if sameElement(v, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ToTuple returns the values of the list as a tuple
+func (l *List) ToTuple() Tuple {
+	return Tuple{*l}
+}
+
+// ToSet returns the distinct values of the list as a set
+func (l *List) ToSet() (Set, error) {
+	return NewSet(l.values...)
+}
+
+// hashable returns true if the value can be an element of a Set
+func hashable(v interface{}) bool {
+	if t, ok := v.(Tuple); ok {
+		for _, e := range t.values {
+			if !hashable(e) {
+				return false
+			}
+		}
+		return true
+	}
+	if _, ok := v.(big.Int); ok {
+		return true
+	}
+	_, ok := indexKey(v)
+	return ok
+}
+
+// numberValue returns the value of an integer or of a float which is not NaN
+func numberValue(v interface{}) (*big.Float, bool) {
+	switch x := v.(type) {
+	case float32:
+		return numberValue(float64(x))
+	case float64:
+		if math.IsNaN(x) {
+			return nil, false
+		}
+		return big.NewFloat(x), true
+	}
+	i, ok := bigValue(v)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Float).SetInt(i), true
+}
+
+// sameElement returns true if the hashable values a and b are the same set element
+func sameElement(a, b interface{}) bool {
+	x, ok := a.(Tuple)
+	if !ok {
+		if _, ok := b.(Tuple); ok {
+			return false
+		}
+		if x, ok := numberValue(a); ok {
+			y, ok := numberValue(b)
+			return ok && x.Cmp(y) == 0
+		}
+		return normalizeKey(a) == normalizeKey(b)
+	}
+	y, ok := b.(Tuple)
+	if !ok || len(x.values) != len(y.values) {
+		return false
+	}
+	for i := range x.values {
+		if !sameElement(x.values[i], y.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// asList returns the values of a List, Tuple or Set as a list
+func asList(v interface{}) (List, bool) {
+	switch x := v.(type) {
+	case List:
+		return x, true
+	case Tuple:
+		return x.List, true
+	case Set:
+		return x.List(), true
+	}
+	return List{}, false
+}
+
+// Positional can be embedded in a struct to map it to a tuple: FromStruct encodes its fields
+// as a list in order of declaration and ToStruct decodes them by position from a list.
+type Positional struct{}
+
+// ToStruct maps the elements of the list by position into the fields of a struct, in order of declaration;
+// the struct does not need to embed Positional. The list must have an element for each field, unless
+// the field is optional or has a default value, and extra elements are collected by the field tagged as
+// remain or reported as unknown.
+func (l *List) ToStruct(dest interface{}, excludeAnnotationTag string) error {
+	return l.ToStructWithOptions(dest, StructOptions{ExcludeAnnotationTag: excludeAnnotationTag})
+}
+
+// ToStructWithOptions is like ToStruct, with the options of Dictionary.ToStructWithOptions; with
+// IgnoreUnknownKeys extra elements are ignored.
+func (l *List) ToStructWithOptions(dest interface{}, opts StructOptions) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected pointer to struct, got %T", dest)
+	}
+
+	sd := structDecoder{opts: opts}
+	err := sd.toTuple(*l, v.Elem(), "", opts.TimeFormat)
+	if err != nil {
+		return err
+	}
+	if len(sd.errs) != 0 {
+		return sd.errs
+	}
+	return nil
+}
+
+var (
+	tupleType      = reflect.TypeOf(Tuple{})
+	setType        = reflect.TypeOf(Set{})
+	positionalType = reflect.TypeOf(Positional{})
+)
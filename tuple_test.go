@@ -0,0 +1,326 @@
+//
+// go-rencode v0.1.8 - Go implementation of rencode - fast (basic)
+//                  object serialization similar to bencode
+// Copyright (C) 2015~2019 gdm85 - https://github.com/gdm85/go-rencode/
+
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package rencode
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewSet("a", int8(1), []byte("a"), NewTuple(int8(1), "x"), NewTuple(int8(1), []byte("x")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Length() != 3 {
+		t.Fatalf("expected 3 elements, got %s", FormatText(s))
+	}
+	if !s.Has([]byte("a")) || !s.Has(NewTuple(int8(1), "x")) || !s.Has(int16(1)) || s.Has(NewList()) {
+		t.Errorf("unexpected membership in %s", FormatText(s))
+	}
+
+	// numbers are the same element regardless of their type, as in Python; NaN is never
+	// the same element as another NaN
+	var huge big.Int
+	huge.SetString("18446744073709551616", 10)
+	numbers, err := NewSet(int8(1), 1, int64(1), uint16(1), 1.0, float32(2.5), 2.5, huge, math.NaN(), math.NaN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numbers.Length() != 5 || !numbers.Has(uint64(1)) {
+		t.Errorf("unexpected numeric elements in %s", FormatText(numbers))
+	}
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	err = e.Encode(numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded List
+	err = NewDecoder(&b).Scan(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedSet, err := decoded.ToSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodedSet.Length() != 5 || !decodedSet.Has(1) || !decodedSet.Has(huge) || decodedSet.Has(2) {
+		t.Errorf("unexpected decoded set %s", FormatText(decodedSet))
+	}
+
+	err = s.Add(int8(2), NewList(1))
+	if err != ErrUnhashable {
+		t.Fatalf("expected ErrUnhashable, got %v", err)
+	}
+	if s.Has(int8(2)) {
+		t.Error("set modified by failed Add")
+	}
+
+	if !s.Delete("a") || s.Delete("a") {
+		t.Error("unexpected result of Delete")
+	}
+	other, _ := NewSet(NewTuple(int8(1), "x"), int8(1))
+	if !Equal(s, other, EqualOptions{}) {
+		t.Errorf("expected %s to equal %s", FormatText(s), FormatText(other))
+	}
+	if Equal(s, s.List(), EqualOptions{}) {
+		t.Error("set equal to list")
+	}
+}
+
+func TestTypedText(t *testing.T) {
+	t.Parallel()
+
+	const text = `{'pair': (1, b'a'), 'one': (2,), 'none': (), 'ids': {3, 4}, 'empty': set(), 'list': [{(5, 6)}]}`
+	v, err := ParseTypedText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(v) != `{b'pair': (1, b'a'), b'one': (2,), b'none': (), b'ids': {3, 4}, b'empty': set(), b'list': [{(5, 6)}]}` {
+		t.Errorf("unexpected formatting of parsed value: %s", FormatText(v))
+	}
+
+	d := v.(Dictionary)
+	ids, _ := d.Get("ids")
+	if s, ok := ids.(Set); !ok || !s.Has(int8(4)) {
+		t.Errorf("expected set, got %T", ids)
+	}
+
+	v, err = ParseText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(v) != `{b'pair': [1, b'a'], b'one': [2], b'none': [], b'ids': [3, 4], b'empty': [], b'list': [[[5, 6]]]}` {
+		t.Errorf("unexpected formatting of parsed value: %s", FormatText(v))
+	}
+
+	for _, input := range []string{"{[1]}", "{1, 2: 3}", "set(1)"} {
+		_, err := ParseTypedText(input)
+		if err == nil {
+			t.Errorf("expected failure for %q", input)
+		}
+	}
+}
+
+func TestEncodeTupleSet(t *testing.T) {
+	t.Parallel()
+
+	s, _ := NewSet("b", "a")
+	var b1, b2 bytes.Buffer
+	e := NewEncoder(&b1)
+	err := e.Encode(NewTuple(int8(1), s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = NewEncoder(&b2)
+	err = e.Encode(NewList(int8(1), NewList("b", "a")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b1.Bytes(), b2.Bytes()) {
+		t.Fatalf("expected %v, got %v", b2.Bytes(), b1.Bytes())
+	}
+
+	var tuple Tuple
+	var set Set
+	err = NewDecoder(&b1).Scan(&tuple)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tuple.Scan(new(int8), &set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(set, s, EqualOptions{}) {
+		t.Errorf("expected %s, got %s", FormatText(s), FormatText(set))
+	}
+}
+
+type tupleAddress struct {
+	Positional
+	Host string
+	Port int32
+	Tags []string `rencode:"remain"`
+}
+
+type tuplePeer struct {
+	Address  tupleAddress
+	Weights  [2]float64
+	Previous *tupleAddress
+}
+
+func TestPositionalStruct(t *testing.T) {
+	t.Parallel()
+
+	peer := tuplePeer{
+		Address: tupleAddress{Host: "localhost", Port: 58846, Tags: []string{"a", "b"}},
+		Weights: [2]float64{0.5, 1},
+	}
+	d, err := FromStruct(peer, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{'address': ['localhost', 58846, 'a', 'b'], 'weights': [0.5, 1.0], 'previous': None}`
+	if FormatText(d) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, FormatText(d))
+	}
+
+	var decoded tuplePeer
+	err = d.ToStruct(&decoded, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(fromStructValue(t, decoded), d, EqualOptions{}) {
+		t.Errorf("unexpected decoded value %+v", decoded)
+	}
+
+	// typed values are accepted where lists are expected
+	v, err := ParseTypedText(`{'address': ('example.org', 80), 'weights': (1.0, 2.0), 'previous': ('localhost', 1)}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d = v.(Dictionary)
+	decoded = tuplePeer{}
+	err = d.ToStruct(&decoded, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Address.Host != "example.org" || decoded.Weights[1] != 2 || decoded.Previous.Port != 1 || decoded.Address.Tags == nil {
+		t.Errorf("unexpected decoded value %+v", decoded)
+	}
+
+	d.Set("address", NewList("example.org"))
+	err = d.ToStruct(&decoded, "")
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path != "address[1]" || fe.Err != ErrMissingField {
+		t.Errorf("expected missing field error, got %v", err)
+	}
+	d.Set("address", Dictionary{})
+	err = d.ToStruct(&decoded, "")
+	if !errors.As(err, &fe) || fe.Path != "address" {
+		t.Errorf("expected field error, got %v", err)
+	}
+}
+
+func TestListToStruct(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		Name  string
+		Count int32
+		Rate  float64 `rencode:"default=1.5"`
+	}
+
+	var r record
+	l := NewList("x", int8(3))
+	err := l.ToStruct(&r, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != (record{"x", 3, 1.5}) {
+		t.Errorf("unexpected value %+v", r)
+	}
+
+	l.Add(2.0, "extra", nil)
+	err = l.ToStructWithOptions(&r, StructOptions{AllErrors: true})
+	var errs StructErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].(*FieldError).Path != "[3]" || !errors.Is(errs[1], ErrUnknownKey) {
+		t.Fatalf("expected unknown element errors, got %v", err)
+	}
+	err = l.ToStructWithOptions(&r, StructOptions{IgnoreUnknownKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Rate != 2 {
+		t.Errorf("unexpected value %+v", r)
+	}
+
+	var d Dictionary
+	for _, dest := range []interface{}{nil, (*record)(nil), r} {
+		if err := l.ToStructWithOptions(dest, StructOptions{}); err == nil {
+			t.Errorf("expected list failure for destination %#v", dest)
+		}
+		if err := d.ToStructWithOptions(dest, StructOptions{}); err == nil {
+			t.Errorf("expected dictionary failure for destination %#v", dest)
+		}
+	}
+
+	tuple, err := FromStructTuple(&r, StructOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatText(tuple) != `('x', 3, 2.0)` {
+		t.Errorf("unexpected tuple %s", FormatText(tuple))
+	}
+}
+
+func TestJSONTypedAnnotations(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	err := FromJSON(strings.NewReader(`{"tuple":[{"int8":1},{"set":[{"text":"a"}]}]}`), &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected bytes.Buffer
+	e := NewEncoder(&expected)
+	err = e.Encode(NewList(int8(1), NewList([]byte("a"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), expected.Bytes()) {
+		t.Fatalf("expected %v, got %v", expected.Bytes(), b.Bytes())
+	}
+
+	err = FromJSON(strings.NewReader(`{"set":[{"int8":1},{"tuple":[{"int8":1},{"bytes":"YQ=="}]},{"text":"b"}]}`), &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the elements of a set must be hashable and distinct
+	for _, invalid := range []string{
+		`{"set":[{"list":[]}]}`,
+		`{"set":[{"tuple":[{"dict":[]}]}]}`,
+		`{"set":[{"int8":1},{"int64":1}]}`,
+		`{"set":[{"text":"a"},{"bytes":"YQ=="}]}`,
+		`{"set":[{"tuple":[{"int8":1}]},{"tuple":[{"int16":1}]}]}`,
+	} {
+		if err := FromJSON(strings.NewReader(invalid), &b); err == nil {
+			t.Errorf("expected failure for %s", invalid)
+		}
+	}
+}
+
+// fromStructValue returns the dictionary of a struct, failing the test on error
+func fromStructValue(t *testing.T, v interface{}) Dictionary {
+	d, err := FromStruct(v, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}